@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+)
+
+type contextKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user.
+func WithUser(ctx context.Context, user model.User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user stored by the JWT middleware.
+func UserFromContext(ctx context.Context) (model.User, bool) {
+	user, ok := ctx.Value(contextKey{}).(model.User)
+	return user, ok
+}
+
+// CurrentUser returns the authenticated user of the request.
+func CurrentUser(r *http.Request) (model.User, bool) {
+	return UserFromContext(r.Context())
+}
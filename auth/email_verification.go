@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/mail"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+const emailVerificationTTL = 24 * time.Hour
+
+type verifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type resendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// SendEmailVerification issues a verification token for user and mails the link to it.
+func SendEmailVerification(user model.User) error {
+	var raw string
+	err := db.GDB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		raw, err = IssueOneTimeToken(tx, user.ID, model.TokenPurposeEmailVerification, emailVerificationTTL)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return mail.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf("Use the following link to verify your email. It expires in %d hours.\n\n%s\n",
+			int(emailVerificationTTL.Hours()), appLink("/verify-email", raw)),
+	})
+}
+
+func VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var input verifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	err := db.GDB.Transaction(func(tx *gorm.DB) error {
+		userID, err := ConsumeOneTimeToken(tx, input.Token, model.TokenPurposeEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"email_verified":    true,
+			"email_verified_at": time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return linkCustomerByEmail(tx, userID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidOneTimeToken) {
+			response := payload.NewErrorResponse(payload.CodeInvalidToken, "Invalid or expired token")
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+
+		log.Printf("error verifying email: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Email verified successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var input resendVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	go func(email string) {
+		user := model.User{}
+		if err := db.GDB.Where("email = ? AND email_verified = ?", email, false).First(&user).Error; err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("error finding user to verify: %v", err)
+			}
+			return
+		}
+
+		if err := SendEmailVerification(user); err != nil {
+			log.Printf("error sending email verification: %v", err)
+		}
+	}(input.Email)
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "If the email is pending verification, a new link has been sent", nil)
+	payload.ResponseJSON(w, http.StatusAccepted, response)
+}
+
+// linkCustomerByEmail links a freshly verified account to the customer record registered
+// with the same email, unless the account or the customer is already linked.
+func linkCustomerByEmail(tx *gorm.DB, userID uint) error {
+	user := model.User{}
+	if err := tx.First(&user, userID).Error; err != nil {
+		return err
+	}
+	if user.CustomerID != nil {
+		return nil
+	}
+
+	customer := model.Customer{}
+	if err := tx.Where("email = ?", user.Email).First(&customer).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var linked int64
+	if err := tx.Model(&model.User{}).Where("customer_id = ?", customer.ID).Count(&linked).Error; err != nil {
+		return err
+	}
+	if linked > 0 {
+		return nil
+	}
+
+	return tx.Model(&user).Update("customer_id", customer.ID).Error
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// signingKey is one key of the set. Keys other than the active one only verify tokens,
+// which lets tokens signed before a rotation stay valid until they expire.
+type signingKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// KeySet holds the keys used to sign and verify tokens. Without asymmetric keys it falls
+// back to HS256 with API_SECRET.
+type KeySet struct {
+	active *signingKey
+	keys   map[string]*signingKey
+	secret []byte
+}
+
+var (
+	keysMu     sync.RWMutex
+	currentSet *KeySet
+)
+
+// InitKeys loads the signing keys. JWT_KEYS_DIR points to a directory of PEM private keys
+// named <kid>.pem; JWT_ACTIVE_KID picks the signing key, otherwise the last kid in
+// lexical order signs. A single key can instead be given in JWT_PRIVATE_KEY with JWT_KEY_ID.
+func InitKeys() error {
+	set, err := loadKeySet()
+	if err != nil {
+		return err
+	}
+
+	keysMu.Lock()
+	currentSet = set
+	keysMu.Unlock()
+
+	if set.active == nil {
+		log.Println("No JWT signing keys configured, falling back to HS256 with API_SECRET")
+	} else {
+		log.Printf("Signing tokens with key %s (%s), %d verification keys loaded", set.active.ID, set.active.Method.Alg(), len(set.keys))
+	}
+	return nil
+}
+
+func keys() *KeySet {
+	keysMu.RLock()
+	set := currentSet
+	keysMu.RUnlock()
+
+	if set == nil {
+		return &KeySet{secret: []byte(os.Getenv("API_SECRET"))}
+	}
+	return set
+}
+
+func loadKeySet() (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*signingKey), secret: []byte(os.Getenv("API_SECRET"))}
+
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+
+			kid := strings.TrimSuffix(filepath.Base(path), ".pem")
+			key, err := parseSigningKey(kid, data)
+			if err != nil {
+				return nil, fmt.Errorf("loading key %s: %w", path, err)
+			}
+			set.keys[kid] = key
+			set.active = key
+		}
+	}
+
+	if pemData := os.Getenv("JWT_PRIVATE_KEY"); pemData != "" {
+		kid := os.Getenv("JWT_KEY_ID")
+		if kid == "" {
+			return nil, errors.New("JWT_KEY_ID is required with JWT_PRIVATE_KEY")
+		}
+
+		key, err := parseSigningKey(kid, []byte(pemData))
+		if err != nil {
+			return nil, fmt.Errorf("loading JWT_PRIVATE_KEY: %w", err)
+		}
+		set.keys[kid] = key
+		set.active = key
+	}
+
+	if kid := os.Getenv("JWT_ACTIVE_KID"); kid != "" {
+		key, ok := set.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("JWT_ACTIVE_KID %q is not a loaded key", kid)
+		}
+		set.active = key
+	}
+
+	return set, nil
+}
+
+func parseSigningKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch key := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &signingKey{ID: kid, Method: jwt.SigningMethodRS256, Private: key, Public: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return &signingKey{ID: kid, Method: jwt.SigningMethodEdDSA, Private: key, Public: key.Public()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
+
+// signClaims signs claims with the active key, adding its kid to the header.
+func signClaims(claims jwt.Claims) (string, error) {
+	set := keys()
+	if set.active == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(set.secret)
+	}
+
+	token := jwt.NewWithClaims(set.active.Method, claims)
+	token.Header["kid"] = set.active.ID
+	return token.SignedString(set.active.Private)
+}
+
+// verificationKey is the jwt.Keyfunc for every token we issue. HS256 is only accepted
+// while no asymmetric keys are configured.
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	set := keys()
+	if set.active == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("method not valid")
+		}
+		return set.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := set.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("method not valid")
+	}
+	return key.Public, nil
+}
@@ -0,0 +1,256 @@
+package auth
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AttemptState is what the limiter remembers about one key (an account or an IP).
+type AttemptState struct {
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+// AttemptStore persists limiter state. The in-memory store only suits a single node;
+// a shared store (e.g. Redis) can implement the same interface. Put passes how long the
+// state matters, after which the store may forget it.
+type AttemptStore interface {
+	Get(key string) (AttemptState, error)
+	Put(key string, state AttemptState, ttl time.Duration) error
+	Delete(key string) error
+}
+
+const (
+	defaultMaxAttemptKeys = 100000
+	attemptSweepInterval  = time.Minute
+)
+
+type memoryAttempt struct {
+	state   AttemptState
+	expires time.Time
+}
+
+// MemoryAttemptStore keeps at most maxKeys entries. Expired entries are swept
+// periodically; when the store is still full, the entry closest to expiring makes room,
+// so a flood of new keys cannot grow it without bound nor push out long locks first.
+type MemoryAttemptStore struct {
+	mu        sync.Mutex
+	states    map[string]memoryAttempt
+	maxKeys   int
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryAttemptStore() *MemoryAttemptStore {
+	return &MemoryAttemptStore{
+		states:  make(map[string]memoryAttempt),
+		maxKeys: defaultMaxAttemptKeys,
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryAttemptStore) Get(key string) (AttemptState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.states[key]
+	if !ok {
+		return AttemptState{}, nil
+	}
+	if !s.now().Before(entry.expires) {
+		delete(s.states, key)
+		return AttemptState{}, nil
+	}
+	return entry.state, nil
+}
+
+func (s *MemoryAttemptStore) Put(key string, state AttemptState, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= attemptSweepInterval {
+		s.sweep(now)
+	}
+	if _, ok := s.states[key]; !ok && len(s.states) >= s.maxKeys {
+		s.sweep(now)
+		if len(s.states) >= s.maxKeys {
+			s.evictSoonest()
+		}
+	}
+
+	s.states[key] = memoryAttempt{state: state, expires: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryAttemptStore) sweep(now time.Time) {
+	s.lastSweep = now
+	for key, entry := range s.states {
+		if !now.Before(entry.expires) {
+			delete(s.states, key)
+		}
+	}
+}
+
+func (s *MemoryAttemptStore) evictSoonest() {
+	var soonestKey string
+	var soonest time.Time
+	found := false
+	for key, entry := range s.states {
+		if !found || entry.expires.Before(soonest) {
+			soonestKey, soonest, found = key, entry.expires, true
+		}
+	}
+	if found {
+		delete(s.states, soonestKey)
+	}
+}
+
+func (s *MemoryAttemptStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, key)
+	return nil
+}
+
+// LoginLimiter locks a key once it reaches its failure threshold. Each failure past the
+// threshold doubles the lock, up to MaxLock. Failures older than Window are forgotten.
+type LoginLimiter struct {
+	Store            AttemptStore
+	AccountThreshold int
+	IPThreshold      int
+	BaseLock         time.Duration
+	MaxLock          time.Duration
+	Window           time.Duration
+}
+
+var loginLimiter = &LoginLimiter{
+	Store:            NewMemoryAttemptStore(),
+	AccountThreshold: 5,
+	IPThreshold:      20,
+	BaseLock:         time.Minute,
+	MaxLock:          time.Hour,
+	Window:           15 * time.Minute,
+}
+
+// InitLoginLimiter reads the limiter settings from the environment, keeping the defaults
+// for anything unset, and stores state in store.
+func InitLoginLimiter(store AttemptStore) {
+	loginLimiter = &LoginLimiter{
+		Store:            store,
+		AccountThreshold: envInt("LOGIN_MAX_ATTEMPTS", 5),
+		IPThreshold:      envInt("LOGIN_MAX_ATTEMPTS_IP", 20),
+		BaseLock:         envDuration("LOGIN_LOCKOUT_BASE", time.Minute),
+		MaxLock:          envDuration("LOGIN_LOCKOUT_MAX", time.Hour),
+		Window:           envDuration("LOGIN_ATTEMPT_WINDOW", 15*time.Minute),
+	}
+}
+
+// Blocked returns how long the caller must wait if any of keys is locked.
+func (l *LoginLimiter) Blocked(now time.Time, keys ...string) (time.Duration, error) {
+	var wait time.Duration
+	for _, key := range keys {
+		state, err := l.Store.Get(key)
+		if err != nil {
+			return 0, err
+		}
+		if remaining := state.LockedUntil.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+	return wait, nil
+}
+
+// Fail records a failed attempt for key and reports the lock it caused, if any.
+func (l *LoginLimiter) Fail(now time.Time, key string, threshold int) (time.Duration, error) {
+	state, err := l.Store.Get(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if now.Sub(state.LastFailure) > l.Window && now.After(state.LockedUntil) {
+		state = AttemptState{}
+	}
+
+	state.Failures++
+	state.LastFailure = now
+
+	var lock time.Duration
+	if threshold > 0 && state.Failures >= threshold {
+		lock = l.BaseLock << uint(min(state.Failures-threshold, 30))
+		if lock > l.MaxLock || lock <= 0 {
+			lock = l.MaxLock
+		}
+		state.LockedUntil = now.Add(lock)
+	}
+
+	// El estado deja de importar cuando vence el bloqueo y la ventana de fallos
+	expires := state.LastFailure.Add(l.Window)
+	if state.LockedUntil.After(expires) {
+		expires = state.LockedUntil
+	}
+	return lock, l.Store.Put(key, state, expires.Sub(now))
+}
+
+func (l *LoginLimiter) Reset(keys ...string) error {
+	for _, key := range keys {
+		if err := l.Store.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UnlockAccount clears the failed attempts and any lock on an account.
+func UnlockAccount(email string, userID uint) error {
+	log.Printf("security: account %s unlocked by an administrator", email)
+	return loginLimiter.Reset(accountKey(email), mfaKey(userID))
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func mfaKey(userID uint) string {
+	return "mfa:" + strconv.FormatUint(uint64(userID), 10)
+}
+
+func ipKey(r *http.Request) string {
+	return "ip:" + clientIP(r)
+}
+
+// clientIP uses X-Forwarded-For only when TRUST_PROXY is set, since clients can forge it.
+func clientIP(r *http.Request) string {
+	if os.Getenv("TRUST_PROXY") == "true" {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func envInt(name string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(name)); err == nil {
+		return value
+	}
+	return fallback
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(name)); err == nil {
+		return value
+	}
+	return fallback
+}
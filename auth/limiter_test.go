@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(store *MemoryAttemptStore) *LoginLimiter {
+	return &LoginLimiter{
+		Store:    store,
+		BaseLock: time.Minute,
+		MaxLock:  10 * time.Minute,
+		Window:   15 * time.Minute,
+	}
+}
+
+func TestLoginLimiterBackoff(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	store := NewMemoryAttemptStore()
+	store.now = func() time.Time { return now }
+	limiter := newTestLimiter(store)
+
+	// Threshold 3: the third failure locks for BaseLock, each further one doubles it
+	// until MaxLock.
+	want := []time.Duration{0, 0, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}
+	for i, lock := range want {
+		got, err := limiter.Fail(now, "account:ana@example.com", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != lock {
+			t.Errorf("failure %d: lock = %v, want %v", i+1, got, lock)
+		}
+
+		wait, err := limiter.Blocked(now, "account:ana@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wait != lock {
+			t.Errorf("failure %d: blocked for %v, want %v", i+1, wait, lock)
+		}
+	}
+}
+
+func TestLoginLimiterForgetsOldFailures(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	store := NewMemoryAttemptStore()
+	store.now = func() time.Time { return now }
+	limiter := newTestLimiter(store)
+
+	for i := 0; i < 2; i++ {
+		if _, err := limiter.Fail(now, "ip:10.0.0.1", 3); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now = now.Add(limiter.Window + time.Second)
+	lock, err := limiter.Fail(now, "ip:10.0.0.1", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lock != 0 {
+		t.Errorf("failures outside the window still counted, lock = %v", lock)
+	}
+}
+
+func TestMemoryAttemptStoreExpiresEntries(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	store := NewMemoryAttemptStore()
+	store.now = func() time.Time { return now }
+
+	if err := store.Put("account:a", AttemptState{Failures: 1}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("account:b", AttemptState{Failures: 1}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if state, _ := store.Get("account:a"); state.Failures != 0 {
+		t.Errorf("expired entry still returned: %+v", state)
+	}
+	if state, _ := store.Get("account:b"); state.Failures != 1 {
+		t.Errorf("live entry lost: %+v", state)
+	}
+
+	// The periodic sweep drops expired entries nobody reads again.
+	if err := store.Put("account:c", AttemptState{Failures: 1}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(attemptSweepInterval + time.Minute)
+	if err := store.Put("account:d", AttemptState{Failures: 1}, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.states["account:c"]; ok {
+		t.Error("expired entry was not swept")
+	}
+}
+
+func TestMemoryAttemptStoreIsBounded(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	store := NewMemoryAttemptStore()
+	store.now = func() time.Time { return now }
+	store.maxKeys = 10
+
+	if err := store.Put("account:locked", AttemptState{Failures: 9}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if err := store.Put(fmt.Sprintf("ip:10.0.0.%d", i), AttemptState{Failures: 1}, 15*time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(store.states) > store.maxKeys {
+		t.Errorf("store holds %d entries, cap is %d", len(store.states), store.maxKeys)
+	}
+	if state, _ := store.Get("account:locked"); state.Failures != 9 {
+		t.Error("a long lock was evicted before entries expiring sooner")
+	}
+}
@@ -3,18 +3,23 @@ package auth
 import (
 	"encoding/json"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/IsraelTeo/api-paw-go/db"
 	"github.com/IsraelTeo/api-paw-go/model"
 	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
 	"github.com/golang-jwt/jwt"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Credentials struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
 }
 
 type Claims struct {
@@ -25,37 +30,114 @@ type Claims struct {
 func Login(w http.ResponseWriter, r *http.Request) {
 	var credentials Credentials
 	if err := json.NewDecoder(r.Body).Decode(&credentials); err != nil {
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&credentials); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	account, ip := accountKey(credentials.Email), ipKey(r)
+	if !loginAllowed(w, account, ip) {
 		return
 	}
 
 	userData, err := userByEmailAndPassword(credentials.Email, credentials.Password)
 	if err != nil {
+		recordLoginFailure(credentials.Email, account, ip)
 		response := payload.NewResponse(payload.MessageTypeError, "Invalid email or password", nil)
 		payload.ResponseJSON(w, http.StatusUnauthorized, response)
 		return
 	}
 
-	token, err := GenerateToken(userData)
+	if err := loginLimiter.Reset(account); err != nil {
+		log.Printf("error resetting login attempts: %v", err)
+	}
+
+	if !userData.EmailVerified {
+		response := payload.NewResponse(payload.MessageTypeError, "Email not verified, check your inbox or request a new verification link", nil)
+		payload.ResponseJSON(w, http.StatusForbidden, response)
+		return
+	}
+
+	if userData.TOTPEnabled {
+		writeMFAChallenge(w, userData.ID, purposeMFA)
+		return
+	}
+
+	if requiresMFA(userData) {
+		writeMFAChallenge(w, userData.ID, purposeMFAEnroll)
+		return
+	}
+
+	writeSession(w, userData, nil)
+}
+
+// writeSession starts a session for user and responds with its tokens plus any extra fields.
+func writeSession(w http.ResponseWriter, user model.User, extra map[string]interface{}) {
+	tokens, err := StartSession(user)
 	if err != nil {
+		log.Printf("error starting session: %v", err)
 		response := payload.NewResponse(payload.MessageTypeError, "Error generating token", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
 	}
 
 	responseMap := map[string]interface{}{
-		"role":  userData.IsAdmin,
-		"token": token,
+		"roles":         user.RoleNames(),
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	}
+	for key, value := range extra {
+		responseMap[key] = value
 	}
 
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Login successfully", responseMap)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
+// loginAllowed answers 429 with Retry-After while any of keys is locked.
+func loginAllowed(w http.ResponseWriter, keys ...string) bool {
+	wait, err := loginLimiter.Blocked(time.Now(), keys...)
+	if err != nil {
+		// Si el almacén falla se permite el intento, el login sigue validando la contraseña
+		log.Printf("error checking login attempts: %v", err)
+		return true
+	}
+	if wait <= 0 {
+		return true
+	}
+
+	log.Printf("security: login blocked for %v (%s)", wait.Round(time.Second), strings.Join(keys, ", "))
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	response := payload.NewResponse(payload.MessageTypeError, "Too many failed attempts, try again later", nil)
+	payload.ResponseJSON(w, http.StatusTooManyRequests, response)
+	return false
+}
+
+func recordLoginFailure(subject, account, ip string) {
+	now := time.Now()
+	log.Printf("security: failed login for %s from %s", subject, strings.TrimPrefix(ip, "ip:"))
+
+	if lock, err := loginLimiter.Fail(now, account, loginLimiter.AccountThreshold); err != nil {
+		log.Printf("error recording login attempt: %v", err)
+	} else if lock > 0 {
+		log.Printf("security: %s locked for %v", account, lock)
+	}
+
+	if lock, err := loginLimiter.Fail(now, ip, loginLimiter.IPThreshold); err != nil {
+		log.Printf("error recording login attempt: %v", err)
+	} else if lock > 0 {
+		log.Printf("security: %s locked for %v", ip, lock)
+	}
+}
+
 func userByEmailAndPassword(email, password string) (model.User, error) {
 	user := model.User{}
-	if err := db.GDB.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := db.GDB.Preload("Roles").Where("email = ?", email).First(&user).Error; err != nil {
 		log.Printf("email invalid: %v", err)
 		return user, err
 	}
@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/golang-jwt/jwt/v4"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	mfaChallengeTTL   = 5 * time.Minute
+	recoveryCodeCount = 10
+
+	purposeMFA       = "mfa"
+	purposeMFAEnroll = "mfa_enroll"
+)
+
+var (
+	ErrInvalidMFACode       = errors.New("invalid authentication code")
+	ErrInvalidMFAChallenge  = errors.New("invalid or expired mfa token")
+	ErrMFAAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled       = errors.New("two-factor enrollment was not started")
+	ErrMFARequiredByRole    = errors.New("two-factor authentication is required for this role")
+	ErrMFANotEnabled        = errors.New("two-factor authentication is not enabled")
+	errRecoveryCodeNotFound = errors.New("recovery code not found")
+)
+
+// requiresMFA reports whether any role of the user makes two-factor authentication mandatory.
+func requiresMFA(user model.User) bool {
+	for _, role := range user.Roles {
+		if role.RequireMFA {
+			return true
+		}
+	}
+	return false
+}
+
+// generateChallengeToken issues the short-lived token returned by Login in place of a
+// session when a second factor is still needed. It carries no session, so ValidateToken
+// rejects it everywhere else.
+func generateChallengeToken(userID uint, purpose string) (string, error) {
+	payload := jwt.MapClaims{
+		"uid":     userID,
+		"purpose": purpose,
+		"iat":     time.Now().Unix(),
+		"exp":     time.Now().Add(mfaChallengeTTL).Unix(),
+	}
+
+	return signClaims(payload)
+}
+
+func parseChallengeToken(raw, purpose string) (uint, error) {
+	token, err := jwt.Parse(raw, verificationKey)
+	if err != nil || !token.Valid {
+		return 0, ErrInvalidMFAChallenge
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != purpose {
+		return 0, ErrInvalidMFAChallenge
+	}
+
+	userID, ok := claims["uid"].(float64)
+	if !ok {
+		return 0, ErrInvalidMFAChallenge
+	}
+	return uint(userID), nil
+}
+
+// BeginTOTPEnrollment stores a new, not yet enabled, secret for the user.
+func BeginTOTPEnrollment(tx *gorm.DB, userID uint) (string, string, error) {
+	var secret string
+	var account string
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		user := model.User{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.TOTPEnabled {
+			return ErrMFAAlreadyEnabled
+		}
+
+		var err error
+		secret, err = GenerateTOTPSecret()
+		if err != nil {
+			return err
+		}
+
+		account = user.Email
+		return tx.Model(&user).Update("totp_secret", secret).Error
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret, TOTPProvisioningURI(mfaIssuer(), account, secret), nil
+}
+
+// ConfirmTOTPEnrollment enables two-factor authentication once the user proves their app
+// produces valid codes, and returns a fresh set of recovery codes.
+func ConfirmTOTPEnrollment(tx *gorm.DB, userID uint, code string) ([]string, error) {
+	var codes []string
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		user := model.User{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		if user.TOTPEnabled {
+			return ErrMFAAlreadyEnabled
+		}
+		if user.TOTPSecret == "" {
+			return ErrMFANotEnrolled
+		}
+
+		step, ok := VerifyTOTP(user.TOTPSecret, code, time.Now())
+		if !ok {
+			return ErrInvalidMFACode
+		}
+
+		err := tx.Model(&user).Updates(map[string]interface{}{
+			"totp_enabled":   true,
+			"totp_last_step": step,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		codes, err = replaceRecoveryCodes(tx, user.ID)
+		return err
+	})
+
+	return codes, err
+}
+
+// VerifyMFA checks either a TOTP code or an unused recovery code for the user.
+func VerifyMFA(tx *gorm.DB, userID uint, code, recoveryCode string) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		user := model.User{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, userID).Error; err != nil {
+			return err
+		}
+		if !user.TOTPEnabled {
+			return ErrMFANotEnabled
+		}
+
+		if recoveryCode != "" {
+			err := useRecoveryCode(tx, user.ID, recoveryCode)
+			if errors.Is(err, errRecoveryCodeNotFound) {
+				return ErrInvalidMFACode
+			}
+			return err
+		}
+
+		step, ok := verifyFreshTOTP(user.TOTPSecret, code, user.TOTPLastStep, time.Now())
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		return tx.Model(&user).Update("totp_last_step", step).Error
+	})
+}
+
+// DisableTOTP turns two-factor authentication off after checking a current code.
+func DisableTOTP(tx *gorm.DB, user model.User, code string) error {
+	if requiresMFA(user) {
+		return ErrMFARequiredByRole
+	}
+
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := VerifyMFA(tx, user.ID, code, ""); err != nil {
+			return err
+		}
+
+		err := tx.Model(&model.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"totp_enabled":   false,
+			"totp_secret":    "",
+			"totp_last_step": 0,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := randomRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := tx.Create(&model.RecoveryCode{UserID: userID, CodeHash: hashToken(code)}).Error; err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+func useRecoveryCode(tx *gorm.DB, userID uint, code string) error {
+	result := tx.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(strings.ToLower(strings.TrimSpace(code)))).
+		Limit(1).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errRecoveryCodeNotFound
+	}
+	return nil
+}
+
+// randomRecoveryCode returns a code such as "k3qz-7fma", easy to type from a printout.
+func randomRecoveryCode() (string, error) {
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	raw := strings.ToLower(totpEncoding.EncodeToString(buf))
+	return raw[:4] + "-" + raw[4:], nil
+}
+
+func mfaIssuer() string {
+	if issuer := os.Getenv("MFA_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "api-paw"
+}
@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+)
+
+type mfaLoginRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type mfaChallengeRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+type mfaConfirmRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type mfaCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type mfaEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// LoginMFA completes a login that Login answered with an mfa challenge.
+func LoginMFA(w http.ResponseWriter, r *http.Request) {
+	var input mfaLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	userID, err := parseChallengeToken(input.MFAToken, purposeMFA)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	account, ip := mfaKey(userID), ipKey(r)
+	if !loginAllowed(w, account, ip) {
+		return
+	}
+
+	if err := VerifyMFA(db.GDB, userID, input.Code, input.RecoveryCode); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			recordLoginFailure(account, account, ip)
+		}
+		writeMFAError(w, err)
+		return
+	}
+
+	if err := loginLimiter.Reset(account); err != nil {
+		log.Printf("error resetting login attempts: %v", err)
+	}
+
+	user := model.User{}
+	if err := db.GDB.Preload("Roles").First(&user, userID).Error; err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	writeSession(w, user, nil)
+}
+
+// EnrollMFAChallenge starts enrollment for a user whose role requires two-factor
+// authentication but who has not set it up yet, using the challenge from Login.
+func EnrollMFAChallenge(w http.ResponseWriter, r *http.Request) {
+	var input mfaChallengeRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	userID, err := parseChallengeToken(input.MFAToken, purposeMFAEnroll)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	writeEnrollment(w, userID)
+}
+
+// ConfirmMFAChallenge enables two-factor authentication for a user enrolling from the
+// login challenge and signs them in.
+func ConfirmMFAChallenge(w http.ResponseWriter, r *http.Request) {
+	var input mfaConfirmRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	userID, err := parseChallengeToken(input.MFAToken, purposeMFAEnroll)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	codes, err := ConfirmTOTPEnrollment(db.GDB, userID, input.Code)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	user := model.User{}
+	if err := db.GDB.Preload("Roles").First(&user, userID).Error; err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	writeSession(w, user, map[string]interface{}{"recovery_codes": codes})
+}
+
+func EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	user, ok := CurrentUser(r)
+	if !ok {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid token", nil)
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	writeEnrollment(w, user.ID)
+}
+
+func ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	user, ok := CurrentUser(r)
+	if !ok {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid token", nil)
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	var input mfaCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	codes, err := ConfirmTOTPEnrollment(db.GDB, user.ID, input.Code)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Two-factor authentication enabled, store the recovery codes safely", map[string]interface{}{
+		"recovery_codes": codes,
+	})
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func DisableMFA(w http.ResponseWriter, r *http.Request) {
+	caller, ok := CurrentUser(r)
+	if !ok {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid token", nil)
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	var input mfaCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	// Los roles del token pueden estar desactualizados, se leen de la base de datos
+	user := model.User{}
+	if err := db.GDB.Preload("Roles").First(&user, caller.ID).Error; err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	if err := DisableTOTP(db.GDB, user, input.Code); err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Two-factor authentication disabled", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func writeEnrollment(w http.ResponseWriter, userID uint) {
+	secret, uri, err := BeginTOTPEnrollment(db.GDB, userID)
+	if err != nil {
+		writeMFAError(w, err)
+		return
+	}
+
+	enrollment := mfaEnrollment{Secret: secret, ProvisioningURI: uri}
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Scan the provisioning URI and confirm with a code", enrollment)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func writeMFAChallenge(w http.ResponseWriter, userID uint, purpose string) {
+	token, err := generateChallengeToken(userID, purpose)
+	if err != nil {
+		log.Printf("error generating mfa challenge: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error generating token", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	message := "Two-factor authentication required"
+	key := "mfa_required"
+	if purpose == purposeMFAEnroll {
+		message = "Two-factor enrollment required for this account"
+		key = "mfa_enrollment_required"
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, message, map[string]interface{}{
+		key:          true,
+		"mfa_token":  token,
+		"expires_in": int(mfaChallengeTTL.Seconds()),
+	})
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func writeMFAError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidMFAChallenge):
+		response := payload.NewErrorResponse(payload.CodeInvalidMFAChallenge, err.Error())
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+	case errors.Is(err, ErrInvalidMFACode):
+		response := payload.NewErrorResponse(payload.CodeInvalidMFACode, err.Error())
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+	case errors.Is(err, ErrMFAAlreadyEnabled):
+		response := payload.NewErrorResponse(payload.CodeMFAAlreadyEnabled, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, ErrMFANotEnrolled):
+		response := payload.NewErrorResponse(payload.CodeMFANotEnrolled, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, ErrMFANotEnabled):
+		response := payload.NewErrorResponse(payload.CodeMFANotEnabled, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, ErrMFARequiredByRole):
+		response := payload.NewErrorResponse(payload.CodeMFARequiredByRole, err.Error())
+		payload.ResponseJSON(w, http.StatusForbidden, response)
+	default:
+		log.Printf("mfa error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+	}
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidOneTimeToken = errors.New("invalid or expired token")
+
+// IssueOneTimeToken creates a single-use token for purpose and returns its raw value.
+// Earlier unused tokens of the same purpose stop working.
+func IssueOneTimeToken(tx *gorm.DB, userID uint, purpose string, ttl time.Duration) (string, error) {
+	err := tx.Model(&model.OneTimeToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	token := model.OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := tx.Create(&token).Error; err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// ConsumeOneTimeToken marks the token as used and returns the user it was issued to.
+func ConsumeOneTimeToken(tx *gorm.DB, raw, purpose string) (uint, error) {
+	token := model.OneTimeToken{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND purpose = ?", hashToken(raw), purpose).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrInvalidOneTimeToken
+		}
+		return 0, err
+	}
+
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return 0, ErrInvalidOneTimeToken
+	}
+
+	if err := tx.Model(&token).Update("used_at", time.Now()).Error; err != nil {
+		return 0, err
+	}
+
+	return token.UserID, nil
+}
@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/mail"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const passwordResetTTL = 30 * time.Minute
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var input forgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	// La respuesta es la misma exista o no el correo, y el envío ocurre en segundo plano
+	// para que el tiempo de respuesta tampoco revele qué cuentas existen
+	go func(email string) {
+		if err := sendPasswordReset(email); err != nil {
+			log.Printf("error sending password reset: %v", err)
+		}
+	}(input.Email)
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "If the email is registered, a reset link has been sent", nil)
+	payload.ResponseJSON(w, http.StatusAccepted, response)
+}
+
+func ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var input resetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error hashed password", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	err = db.GDB.Transaction(func(tx *gorm.DB) error {
+		userID, err := ConsumeOneTimeToken(tx, input.Token, model.TokenPurposePasswordReset)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.User{}).Where("id = ?", userID).Update("password", string(hashedPassword)).Error; err != nil {
+			return err
+		}
+
+		return RevokeUserSessions(tx, userID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidOneTimeToken) {
+			response := payload.NewErrorResponse(payload.CodeInvalidToken, "Invalid or expired token")
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+
+		log.Printf("error resetting password: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Password reset successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func sendPasswordReset(email string) error {
+	user := model.User{}
+	if err := db.GDB.Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	var raw string
+	err := db.GDB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		raw, err = IssueOneTimeToken(tx, user.ID, model.TokenPurposePasswordReset, passwordResetTTL)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return mail.Send(mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Use the following link to reset your password. It expires in %d minutes.\n\n%s\n",
+			int(passwordResetTTL.Minutes()), appLink("/reset-password", raw)),
+	})
+}
+
+// appLink builds a link to the frontend carrying a one-time token.
+func appLink(path, token string) string {
+	return os.Getenv("APP_BASE_URL") + path + "?token=" + token
+}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/mail"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// openTestDB connects to the MySQL database named by TEST_DATABASE_DSN and migrates it.
+// Tests that need a database are skipped when it is not set.
+func openTestDB(t *testing.T) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	conn, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("connecting to test database: %v", err)
+	}
+	db.GDB = conn
+	service.InitValidator()
+
+	if err := db.MigrateDataBase(); err != nil {
+		t.Fatalf("migrating test database: %v", err)
+	}
+}
+
+func createTestUser(t *testing.T, password string) model.User {
+	t.Helper()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := model.User{
+		Email:         fmt.Sprintf("reset-%d@example.com", time.Now().UnixNano()),
+		Password:      string(hashed),
+		EmailVerified: true,
+	}
+	if err := db.GDB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.GDB.Where("user_id = ?", user.ID).Delete(&model.OneTimeToken{})
+		db.GDB.Unscoped().Delete(&user)
+	})
+	return user
+}
+
+func useMemoryMailer(t *testing.T) *mail.MemoryMailer {
+	t.Helper()
+
+	previous := mail.Default()
+	mailer := mail.NewMemoryMailer()
+	mail.SetDefault(mailer)
+	t.Cleanup(func() { mail.SetDefault(previous) })
+	return mailer
+}
+
+// waitForMail waits for the mail ForgotPassword sends in the background.
+func waitForMail(t *testing.T, mailer *mail.MemoryMailer, to string) mail.Message {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, msg := range mailer.Messages() {
+			if msg.To == to {
+				return msg
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("no mail sent to %s", to)
+	return mail.Message{}
+}
+
+func tokenFromMail(t *testing.T, msg mail.Message) string {
+	t.Helper()
+
+	_, rest, found := strings.Cut(msg.Body, "token=")
+	if !found {
+		t.Fatalf("mail has no token link: %q", msg.Body)
+	}
+	return strings.Fields(rest)[0]
+}
+
+func postJSON(handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	handler(w, r)
+	return w
+}
+
+func resetPassword(token, password string) *httptest.ResponseRecorder {
+	return postJSON(ResetPassword, fmt.Sprintf(`{"token":%q,"password":%q}`, token, password))
+}
+
+func TestPasswordResetTokenIsSingleUse(t *testing.T) {
+	openTestDB(t)
+	mailer := useMemoryMailer(t)
+	user := createTestUser(t, "old-password-123")
+
+	if w := postJSON(ForgotPassword, fmt.Sprintf(`{"email":%q}`, user.Email)); w.Code != http.StatusAccepted {
+		t.Fatalf("forgot password: got %d, want %d", w.Code, http.StatusAccepted)
+	}
+	token := tokenFromMail(t, waitForMail(t, mailer, user.Email))
+
+	if w := resetPassword(token, "new-password-123"); w.Code != http.StatusOK {
+		t.Fatalf("first reset: got %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	stored := model.User{}
+	if err := db.GDB.First(&stored, user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(stored.Password), []byte("new-password-123")) != nil {
+		t.Error("password was not changed by the reset")
+	}
+
+	if w := resetPassword(token, "another-password-123"); w.Code != http.StatusBadRequest {
+		t.Fatalf("second reset with the same token: got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPasswordResetTokenExpires(t *testing.T) {
+	openTestDB(t)
+	mailer := useMemoryMailer(t)
+	user := createTestUser(t, "old-password-123")
+
+	postJSON(ForgotPassword, fmt.Sprintf(`{"email":%q}`, user.Email))
+	token := tokenFromMail(t, waitForMail(t, mailer, user.Email))
+
+	err := db.GDB.Model(&model.OneTimeToken{}).
+		Where("token_hash = ?", hashToken(token)).
+		Update("expires_at", time.Now().Add(-time.Minute)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w := resetPassword(token, "new-password-123"); w.Code != http.StatusBadRequest {
+		t.Fatalf("reset with an expired token: got %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestForgotPasswordUnknownEmailSendsNothing(t *testing.T) {
+	openTestDB(t)
+	mailer := useMemoryMailer(t)
+
+	email := fmt.Sprintf("nobody-%d@example.com", time.Now().UnixNano())
+	if w := postJSON(ForgotPassword, fmt.Sprintf(`{"email":%q}`, email)); w.Code != http.StatusAccepted {
+		t.Fatalf("got %d, want %d", w.Code, http.StatusAccepted)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if got := len(mailer.Messages()); got != 0 {
+		t.Errorf("sent %d mails for an unknown address", got)
+	}
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+)
+
+// HasPermission reports whether any of the user's roles grants permission.
+func HasPermission(user model.User, permission string) (bool, error) {
+	if user.HasRole(model.RoleAdmin) {
+		return true, nil
+	}
+
+	roles := user.RoleNames()
+	if len(roles) == 0 {
+		return false, nil
+	}
+
+	var count int64
+	err := db.GDB.Table("role_permissions").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("roles.name IN ? AND permissions.name = ?", roles, permission).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
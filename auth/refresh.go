@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+)
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func Refresh(w http.ResponseWriter, r *http.Request) {
+	var input refreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeInvalidJSON(w)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	pair, err := RotateRefreshToken(input.RefreshToken)
+	if err != nil {
+		code := ""
+		switch {
+		case errors.Is(err, ErrInvalidRefreshToken):
+			code = payload.CodeInvalidToken
+		case errors.Is(err, ErrRefreshTokenReused):
+			code = payload.CodeRefreshTokenReused
+		case errors.Is(err, ErrSessionRevoked):
+			code = payload.CodeSessionRevoked
+		}
+		if code != "" {
+			response := payload.NewErrorResponse(code, "Invalid refresh token")
+			payload.ResponseJSON(w, http.StatusUnauthorized, response)
+			return
+		}
+
+		log.Printf("error rotating refresh token: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error refreshing token", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Token refreshed successfully", pair)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func Logout(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := SessionID(r)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid token", nil)
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	if err := RevokeSession(sessionID); err != nil {
+		log.Printf("error revoking session: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error logging out", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Logout successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
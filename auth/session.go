@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+	ErrSessionRevoked      = errors.New("session revoked")
+)
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// StartSession opens a new session for user and returns its first access and refresh tokens.
+func StartSession(user model.User) (TokenPair, error) {
+	var pair TokenPair
+	err := db.GDB.Transaction(func(tx *gorm.DB) error {
+		session := model.Session{UserID: user.ID}
+		if err := tx.Create(&session).Error; err != nil {
+			return err
+		}
+
+		refresh, err := issueRefreshToken(tx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		access, err := GenerateToken(user, session.ID)
+		if err != nil {
+			return err
+		}
+
+		pair = TokenPair{AccessToken: access, RefreshToken: refresh, ExpiresIn: int(accessTokenTTL.Seconds())}
+		return nil
+	})
+
+	return pair, err
+}
+
+// RotateRefreshToken exchanges a refresh token for a new token pair. Presenting a token
+// that was already rotated means it leaked, so the whole session is revoked.
+func RotateRefreshToken(raw string) (TokenPair, error) {
+	var pair TokenPair
+	var reused, orphaned bool
+	err := db.GDB.Transaction(func(tx *gorm.DB) error {
+		token := model.RefreshToken{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", hashToken(raw)).
+			First(&token).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		session := model.Session{}
+		if err := tx.First(&session, token.SessionID).Error; err != nil {
+			return err
+		}
+		if session.RevokedAt != nil {
+			return ErrSessionRevoked
+		}
+
+		if token.RotatedAt != nil {
+			log.Printf("security: refresh token reuse detected, revoking session %d", session.ID)
+			reused = true
+			return tx.Model(&session).Update("revoked_at", time.Now()).Error
+		}
+		if time.Now().After(token.ExpiresAt) {
+			return ErrInvalidRefreshToken
+		}
+
+		user := model.User{}
+		if err := tx.Preload("Roles").First(&user, session.UserID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// El usuario fue eliminado: la sesión ya no tiene dueño, se revoca
+				orphaned = true
+				return tx.Model(&session).Update("revoked_at", time.Now()).Error
+			}
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&token).Update("rotated_at", now).Error; err != nil {
+			return err
+		}
+
+		refresh, err := issueRefreshToken(tx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		access, err := GenerateToken(user, session.ID)
+		if err != nil {
+			return err
+		}
+
+		pair = TokenPair{AccessToken: access, RefreshToken: refresh, ExpiresIn: int(accessTokenTTL.Seconds())}
+		return nil
+	})
+	if err != nil {
+		return pair, err
+	}
+	if reused {
+		return pair, ErrRefreshTokenReused
+	}
+	if orphaned {
+		return pair, ErrSessionRevoked
+	}
+
+	return pair, nil
+}
+
+func RevokeSession(sessionID uint) error {
+	return db.GDB.Model(&model.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserSessions revokes every open session of a user, e.g. after a password change.
+func RevokeUserSessions(tx *gorm.DB, userID uint) error {
+	return tx.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeOtherSessions revokes every open session of a user except keepSessionID.
+func RevokeOtherSessions(tx *gorm.DB, userID, keepSessionID uint) error {
+	return tx.Model(&model.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func isSessionActive(sessionID uint) (bool, error) {
+	session := model.Session{}
+	if err := db.GDB.First(&session, sessionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return session.RevokedAt == nil, nil
+}
+
+func issueRefreshToken(tx *gorm.DB, sessionID uint) (string, error) {
+	raw, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	token := model.RefreshToken{
+		SessionID: sessionID,
+		TokenHash: hashToken(raw),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+	if err := tx.Create(&token).Error; err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
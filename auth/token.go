@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
@@ -12,17 +11,21 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
-func GenerateToken(user model.User) (string, error) {
+func GenerateToken(user model.User, sessionID uint) (string, error) {
 	payload := jwt.MapClaims{
-		"email":      user.Email,                           // Correo del usuario
-		"authorized": true,                                 // Indica si el usuario está autorizado
-		"is_admin":   user.IsAdmin,                         // Indica si el usuario es administrador
-		"iat":        time.Now().Unix(),                    // Tiempo actual en formato Unix (emisión del token)
-		"exp":        time.Now().Add(time.Hour * 2).Unix(), // Expiración del token en 2 horas
+		"uid":         user.ID,                               // ID del usuario
+		"sid":         sessionID,                             // Sesión a la que pertenece el token, permite revocarlo
+		"email":       user.Email,                            // Correo del usuario
+		"authorized":  true,                                  // Indica si el usuario está autorizado
+		"is_admin":    user.HasRole(model.RoleAdmin),         // Indica si el usuario es administrador, solo según sus roles
+		"roles":       user.RoleNames(),                      // Roles del usuario, sus permisos se resuelven en la base de datos
+		"employee_id": user.EmployeeID,                       // Empleado vinculado a la cuenta, puede ser nulo
+		"customer_id": user.CustomerID,                       // Cliente vinculado a la cuenta, puede ser nulo
+		"iat":         time.Now().Unix(),                     // Tiempo actual en formato Unix (emisión del token)
+		"exp":         time.Now().Add(accessTokenTTL).Unix(), // Expiración del token
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)             // Crea un nuevo token usando el algoritmo de firma HS256 y el payload
-	tokenString, err := token.SignedString([]byte(os.Getenv("API_SECRET"))) // Firma el token con una clave secreta obtenida de las variables de entorno
+	tokenString, err := signClaims(payload) // Firma el token con la clave activa, o con API_SECRET si no hay claves configuradas
 	if err != nil {
 		log.Printf("Error signing the token: %v\n", err)
 		return "", err
@@ -32,36 +35,97 @@ func GenerateToken(user model.User) (string, error) {
 }
 
 func ValidateToken(r *http.Request) (model.User, error) {
+	userData, err := parseToken(r)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	_, ok := userData["email"].(string) //verificamos que el email sea string
+	if !ok {
+		log.Println("Email field missing or not a string in token claims")
+		return model.User{}, fmt.Errorf("email field is missing or invalid in token claims")
+	}
+
+	sessionID, ok := userData["sid"].(float64) //los números de los claims se decodifican como float64
+	if !ok {
+		log.Println("Session field missing in token claims")
+		return model.User{}, fmt.Errorf("session field is missing in token claims")
+	}
+
+	active, err := isSessionActive(uint(sessionID))
+	if err != nil {
+		return model.User{}, fmt.Errorf("checking session: %w", err)
+	}
+	if !active {
+		log.Printf("Token belongs to revoked session %d\n", uint(sessionID))
+		return model.User{}, ErrSessionRevoked
+	}
+
+	userID, _ := userData["uid"].(float64)
+	isAdmin, _ := userData["is_admin"].(bool)
+	response := model.User{
+		Email:   userData["email"].(string), //asignamos el email y nos aseguramos que sea un string
+		IsAdmin: isAdmin,                    //asignamos el rol admin
+	}
+	response.ID = uint(userID)
+
+	response.EmployeeID = uintClaim(userData, "employee_id")
+	response.CustomerID = uintClaim(userData, "customer_id")
+
+	roles, _ := userData["roles"].([]interface{}) //los arreglos de los claims se decodifican como []interface{}
+	for _, role := range roles {
+		if name, ok := role.(string); ok {
+			response.Roles = append(response.Roles, model.Role{Name: name})
+		}
+	}
+
+	return response, nil
+}
+
+// SessionID returns the session the request's access token belongs to.
+func SessionID(r *http.Request) (uint, error) {
+	userData, err := parseToken(r)
+	if err != nil {
+		return 0, err
+	}
+
+	sessionID, ok := userData["sid"].(float64)
+	if !ok {
+		return 0, fmt.Errorf("session field is missing in token claims")
+	}
+	return uint(sessionID), nil
+}
+
+// uintClaim reads an optional numeric claim, returning nil when it is missing or null.
+func uintClaim(claims jwt.MapClaims, name string) *uint {
+	value, ok := claims[name].(float64)
+	if !ok {
+		return nil
+	}
+	id := uint(value)
+	return &id
+}
+
+func parseToken(r *http.Request) (jwt.MapClaims, error) {
 	token := GetToken(r)
 	if token == "" {
 		log.Println("No token found in request")
-		return model.User{}, fmt.Errorf("no token found in request")
+		return nil, fmt.Errorf("no token found in request")
 	}
 
-	jwtToken, err := jwt.Parse(token, validateMethodAndGetSecret) //verifica que el token sea válido
+	jwtToken, err := jwt.Parse(token, verificationKey) //verifica que el token sea válido
 	if err != nil {
 		log.Printf("Token not valid: %v\n", err)
-		return model.User{}, fmt.Errorf("invalid token: %w", err)
+		return nil, fmt.Errorf("invalid token: %w", err)
 	}
 
 	userData, ok := jwtToken.Claims.(jwt.MapClaims) //verificamos que los claims sean del tipo jwt.MapClaims
 	if !ok || !jwtToken.Valid {
 		log.Println("Unable to retrieve payload information or token is invalid")
-		return model.User{}, fmt.Errorf("invalid token claims")
-	}
-
-	_, ok = userData["email"].(string) //verificamos que el email sea string
-	if !ok {
-		log.Println("Email field missing or not a string in token claims")
-		return model.User{}, fmt.Errorf("email field is missing or invalid in token claims")
-	}
-
-	response := model.User{
-		Email:   userData["email"].(string),  //asignamos el email y nos aseguramos que sea un string
-		IsAdmin: userData["is_admin"].(bool), //asignamos el rol admin y nos aseguramos que sea un bool
+		return nil, fmt.Errorf("invalid token claims")
 	}
 
-	return response, nil
+	return userData, nil
 }
 
 func GetToken(r *http.Request) string {
@@ -76,12 +140,3 @@ func GetToken(r *http.Request) string {
 
 	return ""
 }
-
-func validateMethodAndGetSecret(token *jwt.Token) (any, error) {
-	_, ok := token.Method.(*jwt.SigningMethodHMAC)
-	if !ok {
-		return nil, fmt.Errorf("method not valid")
-	}
-
-	return []byte(os.Getenv("API_SECRET")), nil
-}
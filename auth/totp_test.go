@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 key from RFC 6238 appendix B, "12345678901234567890" in base32.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// The RFC lists 8-digit codes; a 6-digit code is the same value modulo 10^6, i.e. its
+// last six digits.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},          // 94287082
+	{1111111109, "081804"},  // 07081804
+	{1111111111, "050471"},  // 14050471
+	{1234567890, "005924"},  // 89005924
+	{2000000000, "279037"},  // 69279037
+	{20000000000, "353130"}, // 65353130
+}
+
+func TestTOTPCodeRFC6238Vectors(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		got, err := TOTPCode(rfc6238Secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("TOTPCode at %d: %v", tt.unix, err)
+		}
+		if got != tt.code {
+			t.Errorf("TOTPCode at %d = %s, want %s", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestVerifyTOTPSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	currentStep := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name   string
+		offset int64
+		want   bool
+	}{
+		{name: "current step", offset: 0, want: true},
+		{name: "previous step", offset: -1, want: true},
+		{name: "next step", offset: 1, want: true},
+		{name: "two steps behind", offset: -2, want: false},
+		{name: "two steps ahead", offset: 2, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := TOTPCode(rfc6238Secret, now.Add(time.Duration(tt.offset*totpPeriod)*time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			step, ok := VerifyTOTP(rfc6238Secret, code, now)
+			if ok != tt.want {
+				t.Fatalf("VerifyTOTP ok = %v, want %v", ok, tt.want)
+			}
+			if ok && step != currentStep+tt.offset {
+				t.Errorf("matched step %d, want %d", step, currentStep+tt.offset)
+			}
+		})
+	}
+}
+
+func TestVerifyTOTPRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870822", "abcdef", "287083"} {
+		if _, ok := VerifyTOTP(rfc6238Secret, code, now); ok {
+			t.Errorf("VerifyTOTP accepted %q", code)
+		}
+	}
+
+	if _, ok := VerifyTOTP("not base32!", "287082", now); ok {
+		t.Error("VerifyTOTP accepted a code for an invalid secret")
+	}
+
+	if _, ok := VerifyTOTP(rfc6238Secret, " 287082 ", now); !ok {
+		t.Error("VerifyTOTP should ignore surrounding whitespace")
+	}
+}
+
+func TestVerifyFreshTOTPRejectsReplay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, err := TOTPCode(rfc6238Secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, ok := verifyFreshTOTP(rfc6238Secret, code, 0, now)
+	if !ok {
+		t.Fatal("first use of a valid code was rejected")
+	}
+
+	if _, ok := verifyFreshTOTP(rfc6238Secret, code, step, now); ok {
+		t.Error("the same code was accepted twice")
+	}
+
+	// A code from the previous step is still inside the skew window but older than the
+	// one already used, so it must be rejected too.
+	previous, err := TOTPCode(rfc6238Secret, now.Add(-totpPeriod*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := verifyFreshTOTP(rfc6238Secret, previous, step, now); ok {
+		t.Error("an older code was accepted after a newer one")
+	}
+
+	next, err := TOTPCode(rfc6238Secret, now.Add(totpPeriod*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := verifyFreshTOTP(rfc6238Secret, next, step, now); !ok {
+		t.Error("a newer code within the skew window was rejected")
+	}
+}
+
+func TestGenerateTOTPSecretRoundTrip(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	code, err := TOTPCode(secret, now)
+	if err != nil {
+		t.Fatalf("generated secret is not usable: %v", err)
+	}
+	if _, ok := VerifyTOTP(secret, code, now); !ok {
+		t.Error("code from a generated secret did not verify")
+	}
+}
@@ -0,0 +1,13 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/payload"
+)
+
+// writeInvalidJSON answers a request body that could not be decoded.
+func writeInvalidJSON(w http.ResponseWriter) {
+	response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+	payload.ResponseJSON(w, http.StatusBadRequest, response)
+}
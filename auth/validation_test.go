@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+)
+
+// These requests fail validation before any database access, so they run without
+// TEST_DATABASE_DSN.
+func TestAuthHandlersReportFieldErrors(t *testing.T) {
+	service.InitValidator()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+		want    map[string]string
+	}{
+		{name: "login", handler: Login, body: `{"email":"nope"}`, want: map[string]string{"email": "email", "password": "required"}},
+		{name: "login mfa", handler: LoginMFA, body: `{"mfa_token":"t"}`, want: map[string]string{"code": "required_without"}},
+		{name: "enroll mfa challenge", handler: EnrollMFAChallenge, body: `{}`, want: map[string]string{"mfa_token": "required"}},
+		{name: "confirm mfa challenge", handler: ConfirmMFAChallenge, body: `{"code":"123456"}`, want: map[string]string{"mfa_token": "required"}},
+		{name: "forgot password", handler: ForgotPassword, body: `{"email":""}`, want: map[string]string{"email": "required"}},
+		{name: "reset password", handler: ResetPassword, body: `{"token":"t","password":"short"}`, want: map[string]string{"password": "min"}},
+		{name: "verify email", handler: VerifyEmail, body: `{}`, want: map[string]string{"token": "required"}},
+		{name: "resend verification", handler: ResendVerification, body: `{"email":"nope"}`, want: map[string]string{"email": "email"}},
+		{name: "refresh", handler: Refresh, body: `{}`, want: map[string]string{"refresh_token": "required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(tt.handler, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", w.Code, w.Body)
+			}
+
+			var problem struct {
+				Code   string               `json:"code"`
+				Errors []service.FieldError `json:"errors"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Code != payload.CodeValidationFailed {
+				t.Errorf("code = %q, want %q", problem.Code, payload.CodeValidationFailed)
+			}
+
+			got := make(map[string]string, len(problem.Errors))
+			for _, fe := range problem.Errors {
+				got[fe.Field] = fe.Rule
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("errors = %v, want %v", got, tt.want)
+			}
+			for field, rule := range tt.want {
+				if got[field] != rule {
+					t.Errorf("field %s rule = %q, want %q", field, got[field], rule)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthHandlersRejectInvalidJSON(t *testing.T) {
+	w := postJSON(Login, `{"email":`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != payload.ContentTypeProblem {
+		t.Errorf("content type = %q, want %q", ct, payload.ContentTypeProblem)
+	}
+}
@@ -29,16 +29,149 @@ func Connection() error {
 }
 
 func MigrateDataBase() error {
+	// Accounts created before email verification existed are treated as verified.
+	verifyExistingUsers := GDB.Migrator().HasTable(&model.User{}) && !GDB.Migrator().HasColumn(&model.User{}, "email_verified")
+	// Accounts created before roles existed need one, or every permission check rejects them.
+	assignRoles := GDB.Migrator().HasTable(&model.User{}) && !GDB.Migrator().HasTable(&model.Role{})
+
 	err := GDB.AutoMigrate(
 		&model.Customer{},
 		&model.Employee{},
 		&model.Pet{},
+		&model.PetMeasurement{},
 		&model.User{},
+		&model.Role{},
+		&model.Permission{},
+		&model.RecoveryCode{},
+		&model.Session{},
+		&model.RefreshToken{},
+		&model.OneTimeToken{},
 		&model.EmployeeType{},
+		&model.Appointment{},
+		&model.ShiftTemplate{},
+		&model.ShiftOverride{},
+		&model.ClinicalRecord{},
+		&model.ClinicalRecordVersion{},
+		&model.Vaccine{},
+		&model.Vaccination{},
+		&model.Service{},
+		&model.Product{},
+		&model.Batch{},
+		&model.StockMovement{},
+		&model.Prescription{},
+		&model.Invoice{},
+		&model.InvoiceLine{},
+		&model.Payment{},
 	)
 
 	if err != nil {
 		return err
 	}
+
+	if verifyExistingUsers {
+		err := GDB.Exec("UPDATE users SET email_verified = true, email_verified_at = NOW()").Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := seedRoles(); err != nil {
+		return err
+	}
+
+	if assignRoles {
+		if err := assignDefaultRoles(); err != nil {
+			return err
+		}
+	}
+
+	if err := createSearchIndexes(); err != nil {
+		return err
+	}
+
+	if err := migrateCustomerPets(); err != nil {
+		return err
+	}
+
+	return migratePetAge()
+}
+
+// searchIndexes are the FULLTEXT indexes used by search.MySQLIndex.
+var searchIndexes = []struct {
+	model   interface{}
+	table   string
+	name    string
+	columns string
+}{
+	{&model.Customer{}, "customers", "ft_customers_search", "first_name, last_name, dni, email, phone_number"},
+	{&model.Pet{}, "pets", "ft_pets_search", "name, race"},
+	{&model.Employee{}, "employees", "ft_employees_search", "first_name, last_name"},
+}
+
+func createSearchIndexes() error {
+	migrator := GDB.Migrator()
+	for _, index := range searchIndexes {
+		if migrator.HasIndex(index.model, index.name) {
+			continue
+		}
+
+		err := GDB.Exec("ALTER TABLE " + index.table + " ADD FULLTEXT INDEX " + index.name + " (" + index.columns + ")").Error
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// migrateCustomerPets moves the legacy customers.pet_id column into the customer_pets
+// join table and drops it, along with the foreign key that cascaded pet deletes to owners.
+// MySQL commits DDL implicitly, so the steps cannot share a transaction. Each one is
+// idempotent instead, and the column goes last, so a run that fails halfway is simply
+// repeated on the next start.
+func migrateCustomerPets() error {
+	migrator := GDB.Migrator()
+	if !migrator.HasColumn(&model.Customer{}, "pet_id") {
+		return nil
+	}
+
+	err := GDB.Exec(`INSERT IGNORE INTO customer_pets (customer_id, pet_id)
+		SELECT id, pet_id FROM customers WHERE pet_id IS NOT NULL AND pet_id <> 0`).Error
+	if err != nil {
+		return err
+	}
+
+	if migrator.HasConstraint(&model.Customer{}, "fk_customers_pet") {
+		if err := migrator.DropConstraint(&model.Customer{}, "fk_customers_pet"); err != nil {
+			return err
+		}
+	}
+
+	return migrator.DropColumn(&model.Customer{}, "pet_id")
+}
+
+// migratePetAge replaces the static pets.age column with a birth date and seeds the
+// weight history with each pet's current weight. Birth dates derived from age are only
+// accurate to the year. As in migrateCustomerPets every step is idempotent and the
+// column is dropped last, since the DDL cannot be rolled back.
+func migratePetAge() error {
+	migrator := GDB.Migrator()
+	if !migrator.HasColumn(&model.Pet{}, "age") {
+		return nil
+	}
+
+	err := GDB.Exec(`UPDATE pets SET birth_date = DATE_SUB(CURDATE(), INTERVAL age YEAR)
+		WHERE birth_date IS NULL AND age IS NOT NULL AND age > 0`).Error
+	if err != nil {
+		return err
+	}
+
+	err = GDB.Exec(`INSERT INTO pet_measurements (created_at, pet_id, measured_at, weight)
+		SELECT NOW(), id, updated_at, weight FROM pets
+		WHERE weight > 0 AND deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM pet_measurements WHERE pet_measurements.pet_id = pets.id)`).Error
+	if err != nil {
+		return err
+	}
+
+	return migrator.DropColumn(&model.Pet{}, "age")
+}
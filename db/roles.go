@@ -0,0 +1,122 @@
+package db
+
+import (
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+)
+
+// rolePermissions is the default permission matrix. Seeding only adds what is missing,
+// so permissions granted later in the database are kept.
+var rolePermissions = map[string][]string{
+	model.RoleVet: {
+		"search:read",
+		"customers:read",
+		"pets:read", "pets:write",
+		"appointments:read", "appointments:write",
+		"records:read", "records:write",
+		"vaccines:read", "vaccinations:read", "vaccinations:write",
+		"services:read",
+		"products:read", "stock:read", "stock:write",
+		"prescriptions:read", "prescriptions:write", "prescriptions:fill",
+		"invoices:read",
+		"shifts:read",
+	},
+	model.RoleReceptionist: {
+		"search:read",
+		"customers:read", "customers:write",
+		"pets:read", "pets:write",
+		"appointments:read", "appointments:write",
+		"vaccines:read", "vaccinations:read",
+		"services:read",
+		"products:read", "stock:read",
+		"prescriptions:read", "prescriptions:fill",
+		"invoices:read", "invoices:write", "payments:write",
+		"shifts:read",
+	},
+	model.RoleGroomer: {
+		"search:read",
+		"customers:read",
+		"pets:read",
+		"appointments:read", "appointments:write",
+		"services:read",
+		"shifts:read",
+	},
+	model.RoleCustomer: {
+		"portal:access",
+	},
+}
+
+// adminPermissions lists every permission; the admin role is granted all of them.
+var adminPermissions = []string{
+	"users:read", "users:write",
+	"roles:read", "roles:write",
+	"types:read", "types:write",
+	"employees:read", "employees:write",
+	"shifts:read", "shifts:write",
+	"customers:read", "customers:write",
+	"pets:read", "pets:write",
+	"appointments:read", "appointments:write", "appointments:delete",
+	"records:read", "records:write",
+	"vaccines:read", "vaccines:write",
+	"vaccinations:read", "vaccinations:write",
+	"services:read", "services:write",
+	"products:read", "products:write",
+	"stock:read", "stock:write", "stock:reconcile",
+	"prescriptions:read", "prescriptions:write", "prescriptions:fill",
+	"invoices:read", "invoices:write", "invoices:void",
+	"payments:write",
+	"portal:access",
+	"search:read",
+}
+
+// seedRoles creates the default roles and permissions and moves users flagged with the
+// legacy is_admin column into the admin role. The flag is cleared once moved, so roles
+// are the only source of admin rights and removing the admin role demotes the user.
+func seedRoles() error {
+	return GDB.Transaction(func(tx *gorm.DB) error {
+		matrix := map[string][]string{model.RoleAdmin: adminPermissions}
+		for role, permissions := range rolePermissions {
+			matrix[role] = permissions
+		}
+
+		for name, permissions := range matrix {
+			role := model.Role{}
+			if err := tx.Where(model.Role{Name: name}).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+
+			for _, permissionName := range permissions {
+				permission := model.Permission{}
+				if err := tx.Where(model.Permission{Name: permissionName}).FirstOrCreate(&permission).Error; err != nil {
+					return err
+				}
+
+				err := tx.Exec("INSERT IGNORE INTO role_permissions (role_id, permission_id) VALUES (?, ?)", role.ID, permission.ID).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		err := tx.Exec(`INSERT IGNORE INTO user_roles (user_id, role_id)
+			SELECT users.id, roles.id FROM users, roles
+			WHERE users.is_admin = true AND roles.name = ?`, model.RoleAdmin).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec("UPDATE users SET is_admin = false WHERE is_admin = true").Error
+	})
+}
+
+// assignDefaultRoles gives each user without a role the one matching its account: customer
+// when it is linked to a customer, receptionist otherwise. Legacy admins already got the
+// admin role from seedRoles. It only runs on the upgrade that introduces roles, so a user
+// an admin later strips of every role is not granted one again on restart.
+func assignDefaultRoles() error {
+	return GDB.Exec(`INSERT IGNORE INTO user_roles (user_id, role_id)
+		SELECT users.id, roles.id FROM users
+		JOIN roles ON roles.name = CASE WHEN users.customer_id IS NULL THEN ? ELSE ? END
+		WHERE NOT EXISTS (SELECT 1 FROM user_roles WHERE user_roles.user_id = users.id)`,
+		model.RoleReceptionist, model.RoleCustomer).Error
+}
@@ -0,0 +1,362 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+type appointmentStatusInput struct {
+	Status string `json:"status"`
+}
+
+func GetAppointmentById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	appointment := model.Appointment{}
+	if err := preloadAppointment(db.GDB).First(&appointment, id).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Appointment was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointment found", appointment)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetAllAppointments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	var appointments []model.Appointment
+	if err := preloadAppointment(db.GDB).Order("start_at").Find(&appointments).Error; err != nil {
+		log.Printf("appointments list not found: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Appointments not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(appointments)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Appointments List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointments found", appointments)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetEmployeeAppointments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	date := time.Now()
+	if raw := r.URL.Query().Get("date"); raw != "" {
+		if date, err = time.ParseInLocation("2006-01-02", raw, time.Local); err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Invalid date format, expected YYYY-MM-DD", nil)
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+	}
+
+	from, to, err := service.AppointmentPeriod(date, r.URL.Query().Get("period"))
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid period, expected day or week", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employee := model.Employee{}
+	if err := db.GDB.First(&employee, id).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	var appointments []model.Appointment
+	err = preloadAppointment(db.GDB).
+		Where("employee_id = ? AND start_at >= ? AND start_at < ?", employee.ID, from, to).
+		Order("start_at").
+		Find(&appointments).Error
+	if err != nil {
+		log.Printf("error listing employee appointments: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(appointments)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Appointments List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointments found", appointments)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveAppointment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	appointment := model.Appointment{}
+	if err := json.NewDecoder(r.Body).Decode(&appointment); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&appointment); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	appointment.Status = model.AppointmentStatusScheduled
+	if err := service.BookAppointment(db.GDB, &appointment); err != nil {
+		writeBookingError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointment created successfully", appointment)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func UpdateAppointment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	appointment := model.Appointment{}
+	if err := db.GDB.First(&appointment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Appointment not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("appointment not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	if appointment.Status != model.AppointmentStatusScheduled {
+		response := payload.NewErrorResponse(payload.CodeInvalidStatusTransition, "Only scheduled appointments can be rescheduled")
+		payload.ResponseJSON(w, http.StatusConflict, response)
+		return
+	}
+
+	var input model.Appointment
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("error decoding request body: %v", err)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	appointment.PetID = input.PetID
+	appointment.CustomerID = input.CustomerID
+	appointment.EmployeeID = input.EmployeeID
+	appointment.StartAt = input.StartAt
+	appointment.EndAt = input.EndAt
+	appointment.ServiceID = input.ServiceID
+	appointment.Reason = input.Reason
+
+	if err := service.BookAppointment(db.GDB, &appointment); err != nil {
+		writeBookingError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointment updated successfully", appointment)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func UpdateAppointmentStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	var input appointmentStatusInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("error decoding request body: %v", err)
+		return
+	}
+
+	if !service.IsValidAppointmentStatus(input.Status) {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid appointment status", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	appointment := model.Appointment{}
+	if err := db.GDB.First(&appointment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Appointment not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("appointment not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	if !service.CanTransitionAppointment(appointment.Status, input.Status) {
+		response := payload.NewErrorResponse(payload.CodeInvalidStatusTransition, "Appointment cannot change from "+appointment.Status+" to "+input.Status)
+		payload.ResponseJSON(w, http.StatusConflict, response)
+		return
+	}
+
+	appointment.Status = input.Status
+	if err := db.GDB.Model(&appointment).Update("status", appointment.Status).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error updating appointment", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error updating appointment status: %v", err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointment status updated successfully", appointment)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func DeleteAppointment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response := payload.NewResponse(payload.MessageTypeError, "Method delete not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	appointment := model.Appointment{}
+	if err := db.GDB.First(&appointment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Appointment not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("appointment not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	if err := db.GDB.Delete(&appointment).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting appointment", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error deleting appointment: %v", err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointment deleted successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func preloadAppointment(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Pet").Preload("Customer").Preload("Employee.EmployeeType").Preload("Service")
+}
+
+func writeBookingError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response := payload.NewResponse(payload.MessageTypeError, "Pet, employee or service not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+	case errors.Is(err, service.ErrInvalidTimeSlot):
+		response := payload.NewErrorResponse(payload.CodeInvalidTimeSlot, err.Error())
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+	case errors.Is(err, service.ErrEmployeeNotQualified):
+		response := payload.NewErrorResponse(payload.CodeEmployeeNotQualified, err.Error())
+		payload.ResponseJSON(w, http.StatusUnprocessableEntity, response)
+	case errors.Is(err, service.ErrPetNotOwned):
+		response := payload.NewErrorResponse(payload.CodePetNotOwned, "Pet does not belong to customer")
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+	case errors.Is(err, service.ErrEmployeeDoubleBooked):
+		response := payload.NewErrorResponse(payload.CodeEmployeeDoubleBooked, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, service.ErrPetDoubleBooked):
+		response := payload.NewErrorResponse(payload.CodePetDoubleBooked, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, service.ErrEmployeeUnavailable):
+		response := payload.NewErrorResponse(payload.CodeEmployeeUnavailable, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	default:
+		log.Printf("error booking appointment: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+	}
+}
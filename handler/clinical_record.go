@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+type clinicalRecordInput struct {
+	Kind string `json:"kind" validate:"required,oneof=visit_note diagnosis treatment vital_signs"`
+	model.ClinicalRecordVersion
+}
+
+func GetClinicalRecords(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	petID, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := db.GDB.First(&model.Pet{}, petID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	var records []model.ClinicalRecord
+	err = db.GDB.
+		Preload("Versions", func(tx *gorm.DB) *gorm.DB { return tx.Order("version") }).
+		Preload("Versions.Employee").
+		Where("pet_id = ?", petID).
+		Order("created_at DESC").
+		Find(&records).Error
+	if err != nil {
+		log.Printf("error listing clinical records: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(records)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Clinical records List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	service.SetCurrentClinicalVersion(records)
+	for i := range records {
+		records[i].Versions = nil
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Clinical records found", records)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetClinicalRecordById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	record, ok := findClinicalRecord(w, r)
+	if !ok {
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Clinical record found", record)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveClinicalRecord(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	petID, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	input := clinicalRecordInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employeeID, ok := requireCallerEmployee(w, r)
+	if !ok {
+		return
+	}
+	input.EmployeeID = employeeID
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if err := db.GDB.First(&model.Pet{}, petID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	if err := db.GDB.First(&model.Employee{}, input.EmployeeID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	record := model.ClinicalRecord{PetID: petID, Kind: input.Kind}
+	version := input.ClinicalRecordVersion
+	if err := service.CreateClinicalRecord(db.GDB, &record, &version); err != nil {
+		log.Printf("error creating clinical record: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Clinical record created successfully", record)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func SaveClinicalRecordVersion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	record, ok := findClinicalRecord(w, r)
+	if !ok {
+		return
+	}
+
+	version := model.ClinicalRecordVersion{}
+	if err := json.NewDecoder(r.Body).Decode(&version); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employeeID, ok := requireCallerEmployee(w, r)
+	if !ok {
+		return
+	}
+	version.EmployeeID = employeeID
+
+	if err := service.ValidateEntity(&version); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	empty := service.IsEmpty(version.CorrectionReason)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeError, "Correction reason cannot be empty", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := db.GDB.First(&model.Employee{}, version.EmployeeID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	record.Versions = nil
+	if err := service.AppendClinicalRecordVersion(db.GDB, &record, &version); err != nil {
+		log.Printf("error appending clinical record version: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Clinical record corrected successfully", record)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func findClinicalRecord(w http.ResponseWriter, r *http.Request) (model.ClinicalRecord, bool) {
+	record := model.ClinicalRecord{}
+
+	petID, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return record, false
+	}
+
+	recordID, err := parseUintParam(r, "recordId")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid record ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return record, false
+	}
+
+	err = db.GDB.
+		Preload("Versions", func(tx *gorm.DB) *gorm.DB { return tx.Order("version") }).
+		Preload("Versions.Employee").
+		Where("pet_id = ?", petID).
+		First(&record, recordID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Clinical record not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return record, false
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return record, false
+	}
+
+	records := []model.ClinicalRecord{record}
+	service.SetCurrentClinicalVersion(records)
+	return records[0], true
+}
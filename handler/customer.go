@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/IsraelTeo/api-paw-go/db"
 	"github.com/IsraelTeo/api-paw-go/model"
@@ -25,16 +26,26 @@ func GetCustomerById(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
 	id := params["id"]
 	customer := model.Customer{}
-	if err := db.GDB.Preload("Pet").First(&customer, id).Error; err != nil {
+	if err := db.GDB.Preload("Pets").First(&customer, id).Error; err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Customer was not found", nil)
 		payload.ResponseJSON(w, http.StatusNotFound, response)
 		return
 	}
 
+	if notModified(w, r, customerETag(customer)) {
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Customer found", customer)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
+var customerListSpec = service.ListSpec{
+	Filters:     map[string]string{"first_name": "first_name", "last_name": "last_name", "dni": "dni", "email": "email"},
+	Sorts:       map[string]string{"id": "id", "first_name": "first_name", "last_name": "last_name", "created_at": "created_at"},
+	DefaultSort: "id",
+}
+
 func GetAllCustomers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
@@ -42,8 +53,15 @@ func GetAllCustomers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var customers []model.Customer
-	if err := db.GDB.Preload("Pet").Find(&customers).Error; err != nil {
+	query, err := service.ParseListQuery(r.URL.Query(), customerListSpec)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, err.Error(), nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	customers, meta, err := service.FindPage[model.Customer](db.GDB.Preload("Pets"), query)
+	if err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Customers were not found", nil)
 		payload.ResponseJSON(w, http.StatusNotFound, response)
 		return
@@ -56,7 +74,7 @@ func GetAllCustomers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := payload.NewResponse(payload.MessageTypeSuccess, "Customers found", customers)
+	response := payload.NewPageResponse(payload.MessageTypeSuccess, "Customers found", customers, meta)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
@@ -75,9 +93,7 @@ func SaveCustomer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := service.ValidateEntity(&customer); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request.", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		service.WriteValidationError(w, err)
 		return
 	}
 
@@ -86,12 +102,25 @@ func SaveCustomer(w http.ResponseWriter, r *http.Request) {
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
 	} else if exists {
-		response := payload.NewResponse(payload.MessageTypeError, "Email already exists", nil)
+		response := payload.NewErrorResponse(payload.CodeEmailTaken, "Email already exists")
 		payload.ResponseJSON(w, http.StatusConflict, response)
 		return
 	}
 
-	if result := db.GDB.Create(&customer); result.Error != nil {
+	err := db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Pets").Create(&customer).Error; err != nil {
+			return err
+		}
+		return service.AttachPets(tx, &customer, customer.PetIDs)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+
+		log.Printf("error creating customer: %v", err)
 		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
@@ -102,8 +131,8 @@ func SaveCustomer(w http.ResponseWriter, r *http.Request) {
 }
 
 func UpdateCustomer(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+	if !isUpdateMethod(r) {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put or patch not permit", nil)
 		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
 		return
 	}
@@ -119,7 +148,7 @@ func UpdateCustomer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	customer := model.Customer{}
-	if err := db.GDB.Preload("Pet").First(&customer, uint(id)).Error; err != nil {
+	if err := db.GDB.Preload("Pets").First(&customer, uint(id)).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response := payload.NewResponse(payload.MessageTypeError, "Customer not found", nil)
 			payload.ResponseJSON(w, http.StatusNotFound, response)
@@ -133,18 +162,15 @@ func UpdateCustomer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var input model.Customer
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
-		log.Printf("bad request %v:", err)
+	etag := customerETag(customer)
+	if !checkIfMatch(w, r, etag) {
 		return
 	}
+	version := customer.UpdatedAt
 
-	if err := service.ValidateEntity(&customer); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
+	var input model.Customer
+	if err := decodeUpdateBody(r, customer, &input); err != nil {
+		writeUpdateBodyError(w, err)
 		return
 	}
 
@@ -154,20 +180,37 @@ func UpdateCustomer(w http.ResponseWriter, r *http.Request) {
 	customer.Email = input.Email
 	customer.PhoneNumber = input.PhoneNumber
 
-	if err := db.GDB.Save(&customer).Error; err != nil {
+	if err := service.ValidateEntity(&customer); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	err = db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := checkCustomerUnchanged(tx, customer.ID, etag); err != nil {
+			return err
+		}
+		return service.SaveIfUnmodified(tx.Omit("Pets"), &customer, version)
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrStaleEntity) {
+			writeStaleEntity(w)
+			return
+		}
+
 		response := payload.NewResponse(payload.MessageTypeError, "Error updating employee", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		log.Printf("error updating employee: %v", err)
 		return
 	}
 
-	if err := db.GDB.Preload("Pet").First(&customer, uint(customer.ID)).Error; err != nil {
+	if err := db.GDB.Preload("Pets").First(&customer, uint(customer.ID)).Error; err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Error loading pet data", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		log.Printf("error loading pet data: %v", err)
 		return
 	}
 
+	w.Header().Set("ETag", customerETag(customer))
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Customer updated successfull", customer)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
@@ -190,7 +233,7 @@ func DeleteCustomer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	customer := model.Customer{}
-	if err := db.GDB.First(&customer, uint(id)).Error; err != nil {
+	if err := db.GDB.Preload("Pets").First(&customer, uint(id)).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			response := payload.NewResponse(payload.MessageTypeError, "Customer not found", nil)
 			payload.ResponseJSON(w, http.StatusNotFound, response)
@@ -204,17 +247,25 @@ func DeleteCustomer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if customer.PetID != 0 {
-		pet := model.Pet{}
-		if err := db.GDB.Delete(&pet, customer.PetID).Error; err != nil {
-			response := payload.NewResponse(payload.MessageTypeError, "Error deleting pet", nil)
-			payload.ResponseJSON(w, http.StatusInternalServerError, response)
-			log.Printf("error deleting pet: %v", err)
-			return
-		}
+	etag := customerETag(customer)
+	if !checkIfMatch(w, r, etag) {
+		return
 	}
 
-	if err := db.GDB.Delete(&customer).Error; err != nil {
+	err = db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := checkCustomerUnchanged(tx, customer.ID, etag); err != nil {
+			return err
+		}
+		if err := tx.Model(&customer).Association("Pets").Clear(); err != nil {
+			return err
+		}
+		return service.DeleteIfUnmodified(tx, &customer, customer.UpdatedAt)
+	})
+	if errors.Is(err, service.ErrStaleEntity) {
+		writeStaleEntity(w)
+		return
+	}
+	if err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Error deleting customer", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		log.Printf("error deleting customer: %v", err)
@@ -224,3 +275,170 @@ func DeleteCustomer(w http.ResponseWriter, r *http.Request) {
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Customer deleted successfull", nil)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
+
+// checkCustomerUnchanged locks the customer and its pets and returns
+// service.ErrStaleEntity when they no longer match etag. The conditional writes only
+// compare the customer row, so a pet edited since the read would otherwise go unnoticed.
+func checkCustomerUnchanged(tx *gorm.DB, id uint, etag string) error {
+	current, err := service.LockCustomer(tx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return service.ErrStaleEntity
+	}
+	if err != nil {
+		return err
+	}
+	if customerETag(current) != etag {
+		return service.ErrStaleEntity
+	}
+	return nil
+}
+
+type customerPetInput struct {
+	PetID uint `json:"pet_id" validate:"required"`
+}
+
+func GetCustomerPets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	customer := model.Customer{}
+	if err := db.GDB.Preload("Pets").First(&customer, id).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Customer was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(customer.Pets)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Pets List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Pets found", customer.Pets)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func AttachCustomerPet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	input := customerPetInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	customer := model.Customer{}
+	if err := db.GDB.First(&customer, id).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Customer was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	if err := service.AttachPets(db.GDB, &customer, []uint{input.PetID}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+
+		log.Printf("error attaching pet: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := db.GDB.Preload("Pets").First(&customer, customer.ID).Error; err != nil {
+		log.Printf("error loading pet data: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error loading pet data", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Pet attached successfully", customer.Pets)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func DetachCustomerPet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response := payload.NewResponse(payload.MessageTypeError, "Method delete not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	petID, err := parseUintParam(r, "petId")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid pet ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	owned, err := service.CustomerOwnsPet(db.GDB, id, petID)
+	if err != nil {
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	if !owned {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet does not belong to customer", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	customer := model.Customer{}
+	customer.ID = id
+	pet := model.Pet{}
+	pet.ID = petID
+	err = db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&customer).Association("Pets").Delete(&pet); err != nil {
+			return err
+		}
+		// The customer's pets are part of its representation, so detaching one must
+		// change its ETag the same way attaching does.
+		return tx.Model(&customer).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		log.Printf("error detaching pet: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Pet detached successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
@@ -32,10 +32,20 @@ func GetEmployeeById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if notModified(w, r, entityETag(employee.ID, employee.UpdatedAt)) {
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Employee found", employee)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
+var employeeListSpec = service.ListSpec{
+	Filters:     map[string]string{"first_name": "first_name", "last_name": "last_name", "dni": "dni", "email": "email", "type_id": "type_id"},
+	Sorts:       map[string]string{"id": "id", "first_name": "first_name", "last_name": "last_name", "created_at": "created_at"},
+	DefaultSort: "id",
+}
+
 func GetAllEmployees(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
@@ -43,8 +53,15 @@ func GetAllEmployees(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var employees []model.Employee
-	if err := db.GDB.Preload("EmployeeType").Find(&employees).Error; err != nil {
+	query, err := service.ParseListQuery(r.URL.Query(), employeeListSpec)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, err.Error(), nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employees, meta, err := service.FindPage[model.Employee](db.GDB.Preload("EmployeeType"), query)
+	if err != nil {
 		log.Printf("employees list not found %v:", err)
 		response := payload.NewResponse(payload.MessageTypeError, "Employees not found", nil)
 		payload.ResponseJSON(w, http.StatusNotFound, response)
@@ -58,7 +75,7 @@ func GetAllEmployees(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := payload.NewResponse(payload.MessageTypeSuccess, "Employees found", employees)
+	response := payload.NewPageResponse(payload.MessageTypeSuccess, "Employees found", employees, meta)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
@@ -85,9 +102,7 @@ func SaveEmployee(w http.ResponseWriter, r *http.Request) {
 	employee.BirthDate = parsedDate
 
 	if err := service.ValidateEntity(&employee); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request.", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		service.WriteValidationError(w, err)
 		return
 	}
 
@@ -96,7 +111,7 @@ func SaveEmployee(w http.ResponseWriter, r *http.Request) {
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
 	} else if exists {
-		response := payload.NewResponse(payload.MessageTypeError, "DNI already exists", nil)
+		response := payload.NewErrorResponse(payload.CodeDNITaken, "DNI already exists")
 		payload.ResponseJSON(w, http.StatusConflict, response)
 		return
 	}
@@ -106,7 +121,7 @@ func SaveEmployee(w http.ResponseWriter, r *http.Request) {
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
 	} else if exists {
-		response := payload.NewResponse(payload.MessageTypeError, "Email already exists", nil)
+		response := payload.NewErrorResponse(payload.CodeEmailTaken, "Email already exists")
 		payload.ResponseJSON(w, http.StatusConflict, response)
 		return
 	}
@@ -116,7 +131,7 @@ func SaveEmployee(w http.ResponseWriter, r *http.Request) {
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
 	} else if exists {
-		response := payload.NewResponse(payload.MessageTypeError, "Phone number already exists", nil)
+		response := payload.NewErrorResponse(payload.CodePhoneNumberTaken, "Phone number already exists")
 		payload.ResponseJSON(w, http.StatusConflict, response)
 		return
 	}
@@ -132,8 +147,8 @@ func SaveEmployee(w http.ResponseWriter, r *http.Request) {
 }
 
 func UpdateEmployee(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		response := payload.NewResponse(payload.MessageTypeError, "Method PUT not allowed", nil)
+	if !isUpdateMethod(r) {
+		response := payload.NewResponse(payload.MessageTypeError, "Method PUT or PATCH not allowed", nil)
 		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
 		return
 	}
@@ -163,18 +178,14 @@ func UpdateEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var input model.Employee
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
-		log.Printf("bad request %v:", err)
+	if !checkIfMatch(w, r, entityETag(employee.ID, employee.UpdatedAt)) {
 		return
 	}
+	version := employee.UpdatedAt
 
-	if err := service.ValidateEntity(&input); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
+	var input model.Employee
+	if err := decodeUpdateBody(r, employee, &input); err != nil {
+		writeUpdateBodyError(w, err)
 		return
 	}
 
@@ -187,7 +198,17 @@ func UpdateEmployee(w http.ResponseWriter, r *http.Request) {
 	employee.TypeID = input.TypeID
 	employee.BirthDateRaw = input.BirthDateRaw
 
-	if err := db.GDB.Save(&employee).Error; err != nil {
+	if err := service.ValidateEntity(&employee); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if err := service.SaveIfUnmodified(db.GDB, &employee, version); err != nil {
+		if errors.Is(err, service.ErrStaleEntity) {
+			writeStaleEntity(w)
+			return
+		}
+
 		log.Printf("Error updating employee: %v", err)
 		response := payload.NewResponse(payload.MessageTypeError, "Error updating employee", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
@@ -201,6 +222,7 @@ func UpdateEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("ETag", entityETag(employee.ID, employee.UpdatedAt))
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Employee updated successfully", employee)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
@@ -237,7 +259,22 @@ func DeleteEmployee(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db.GDB.Delete(&employee)
+	if !checkIfMatch(w, r, entityETag(employee.ID, employee.UpdatedAt)) {
+		return
+	}
+
+	if err := service.DeleteIfUnmodified(db.GDB, &employee, employee.UpdatedAt); err != nil {
+		if errors.Is(err, service.ErrStaleEntity) {
+			writeStaleEntity(w)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting employee", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error deleting employee: %v", err)
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Employee deleted successfull", nil)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/payload"
+)
+
+// NotFound answers requests that match no route.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	response := payload.NewResponse(payload.MessageTypeError, "Route not found", nil)
+	payload.ResponseJSON(w, http.StatusNotFound, response)
+}
+
+// MethodNotAllowed answers requests to a known route with a method it does not serve.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	response := payload.NewResponse(payload.MessageTypeError, "Method not allowed", nil)
+	payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+)
+
+// entityETag derives a strong validator from the row id and its last update. MySQL keeps
+// timestamps to the millisecond, so finer precision would never match a reloaded row.
+func entityETag(id uint, updatedAt time.Time) string {
+	return fmt.Sprintf(`"%d-%d"`, id, updatedAt.UnixMilli())
+}
+
+// customerETag also covers the embedded pets: editing a pet or changing which pets a
+// customer owns changes the representation without touching the customer row.
+func customerETag(customer model.Customer) string {
+	pets := make([]model.Pet, len(customer.Pets))
+	copy(pets, customer.Pets)
+	sort.Slice(pets, func(i, j int) bool { return pets[i].ID < pets[j].ID })
+
+	hash := fnv.New64a()
+	for _, pet := range pets {
+		fmt.Fprintf(hash, "%d:%d;", pet.ID, pet.UpdatedAt.UnixMilli())
+	}
+	return fmt.Sprintf(`"%d-%d-%x"`, customer.ID, customer.UpdatedAt.UnixMilli(), hash.Sum64())
+}
+
+// notModified sets the ETag header and answers 304 when If-None-Match already names it.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	header := r.Header.Get("If-None-Match")
+	if header == "" || !etagListMatches(header, etag, true) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// checkIfMatch enforces If-Match on writes. It answers 412 when the client edited an
+// older version, and 428 when REQUIRE_IF_MATCH is set and the header is missing.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, etag string) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		if os.Getenv("REQUIRE_IF_MATCH") == "true" {
+			response := payload.NewResponse(payload.MessageTypeError, "If-Match header is required", nil)
+			payload.ResponseJSON(w, http.StatusPreconditionRequired, response)
+			return false
+		}
+		return true
+	}
+
+	if !etagListMatches(header, etag, false) {
+		w.Header().Set("ETag", etag)
+		response := payload.NewErrorResponse(payload.CodeStaleEntity, "Resource was modified, reload it and try again")
+		payload.ResponseJSON(w, http.StatusPreconditionFailed, response)
+		return false
+	}
+	return true
+}
+
+// writeStaleEntity answers a conditional write that lost the race against another request.
+func writeStaleEntity(w http.ResponseWriter) {
+	response := payload.NewErrorResponse(payload.CodeStaleEntity, "Resource was modified, reload it and try again")
+	payload.ResponseJSON(w, http.StatusPreconditionFailed, response)
+}
+
+// etagListMatches compares etag against a header value. If-None-Match uses the weak
+// comparison and If-Match the strong one, which never matches a W/ tag.
+func etagListMatches(header, etag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+type stockOutInput struct {
+	Quantity int64  `json:"quantity" validate:"gt=0"`
+	Reason   string `json:"reason" validate:"required,max=255"`
+}
+
+type dispenseInput struct {
+	Quantity int64  `json:"quantity" validate:"gt=0"`
+	PetID    uint   `json:"pet_id" validate:"required"`
+	Reason   string `json:"reason" validate:"max=255"`
+}
+
+func SaveBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	product, ok := findProduct(w, r)
+	if !ok {
+		return
+	}
+
+	batch := model.Batch{}
+	if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employeeID, ok := requireCallerEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	if err := service.ValidateEntity(&batch); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	batch.ID = 0
+	batch.ProductID = product.ID
+	if err := service.ReceiveStock(db.GDB, &batch, &employeeID); err != nil {
+		log.Printf("error receiving stock: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Stock received successfully", batch)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func RemoveStock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	product, ok := findProduct(w, r)
+	if !ok {
+		return
+	}
+
+	input := stockOutInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employeeID, ok := requireCallerEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	movements, err := service.RemoveStock(db.GDB, service.StockRemoval{
+		ProductID:  product.ID,
+		Quantity:   input.Quantity,
+		Kind:       model.StockMovementOut,
+		EmployeeID: &employeeID,
+		Reason:     input.Reason,
+	})
+	if err != nil {
+		writeStockError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Stock removed successfully", movements)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func DispenseStock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	product, ok := findProduct(w, r)
+	if !ok {
+		return
+	}
+
+	input := dispenseInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employeeID, ok := requireCallerEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if err := db.GDB.First(&model.Pet{}, input.PetID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	movements, err := service.RemoveStock(db.GDB, service.StockRemoval{
+		ProductID:  product.ID,
+		Quantity:   input.Quantity,
+		Kind:       model.StockMovementDispense,
+		PetID:      &input.PetID,
+		EmployeeID: &employeeID,
+		Reason:     input.Reason,
+	})
+	if err != nil {
+		writeStockError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Stock dispensed successfully", movements)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func GetStock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	stocks, err := service.ProductStocks(db.GDB)
+	if err != nil {
+		log.Printf("error loading stock: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(stocks)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Stock List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Stock found", stocks)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetExpiringBatches(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	days := defaultDueWithinDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			response := payload.NewResponse(payload.MessageTypeError, "Invalid days, expected a non-negative number", nil)
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+		days = parsed
+	}
+
+	batches, err := service.ExpiringBatches(db.GDB, time.Now().AddDate(0, 0, days))
+	if err != nil {
+		log.Printf("error listing expiring batches: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(batches)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "No batches expiring", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Expiring batches found", batches)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetProductMovements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	product, ok := findProduct(w, r)
+	if !ok {
+		return
+	}
+
+	var movements []model.StockMovement
+	if err := db.GDB.Where("product_id = ?", product.ID).Order("id").Find(&movements).Error; err != nil {
+		log.Printf("error listing stock movements: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(movements)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Stock movements List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Stock movements found", movements)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func ReconcileStock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	discrepancies, err := service.ReconcileStock(db.GDB)
+	if err != nil {
+		log.Printf("error reconciling stock: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if len(discrepancies) == 0 {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Stock matches the movement ledger", discrepancies)
+		payload.ResponseJSON(w, http.StatusOK, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Stock does not match the movement ledger", discrepancies)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func findProduct(w http.ResponseWriter, r *http.Request) (model.Product, bool) {
+	product := model.Product{}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return product, false
+	}
+
+	if err := db.GDB.First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Product not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return product, false
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return product, false
+	}
+
+	return product, true
+}
+
+func writeStockError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInsufficientStock) {
+		response := payload.NewErrorResponse(payload.CodeInsufficientStock, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+		return
+	}
+
+	log.Printf("error removing stock: %v", err)
+	response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+	payload.ResponseJSON(w, http.StatusInternalServerError, response)
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+type customerBalance struct {
+	CustomerID  uint            `json:"customer_id"`
+	Outstanding model.Money     `json:"outstanding"`
+	Invoices    []model.Invoice `json:"invoices"`
+}
+
+func GetInvoiceById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	invoice, ok := findInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Invoice found", invoice)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveInvoice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	invoice := model.Invoice{}
+	if err := json.NewDecoder(r.Body).Decode(&invoice); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&invoice); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	invoice.ID = 0
+	invoice.Number = nil
+	invoice.Status = model.InvoiceStatusDraft
+	invoice.AmountPaid = 0
+	invoice.IssuedAt = nil
+	invoice.PaidAt = nil
+	invoice.VoidedAt = nil
+	invoice.Payments = nil
+	if err := service.ValidateInvoiceReferences(db.GDB, &invoice); err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	if err := service.SaveDraftInvoice(db.GDB, &invoice); err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Invoice created successfully", invoice)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func UpdateInvoice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	invoice, ok := findInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	var input model.Invoice
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("error decoding request body: %v", err)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	invoice.CustomerID = input.CustomerID
+	invoice.AppointmentID = input.AppointmentID
+	invoice.PetID = input.PetID
+	invoice.TaxRateBps = input.TaxRateBps
+	invoice.Discount = input.Discount
+	invoice.Lines = input.Lines
+	invoice.Payments = nil
+
+	if err := service.ValidateInvoiceReferences(db.GDB, &invoice); err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	if err := service.SaveDraftInvoice(db.GDB, &invoice); err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Invoice updated successfully", invoice)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func IssueInvoice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	invoice, ok := findInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	if err := service.IssueInvoice(db.GDB, &invoice); err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Invoice issued successfully", invoice)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func VoidInvoice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	invoice, ok := findInvoice(w, r)
+	if !ok {
+		return
+	}
+
+	if err := service.VoidInvoice(db.GDB, &invoice); err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Invoice voided successfully", invoice)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveInvoicePayment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	payment := model.Payment{}
+	if err := json.NewDecoder(r.Body).Decode(&payment); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&payment); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	payment.ID = 0
+	invoice, err := service.RecordPayment(db.GDB, id, &payment)
+	if err != nil {
+		writeInvoiceError(w, err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Payment recorded successfully", invoice)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func GetCustomerBalance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := db.GDB.First(&model.Customer{}, id).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Customer was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	invoices, outstanding, err := service.CustomerOutstandingInvoices(db.GDB, id)
+	if err != nil {
+		log.Printf("error loading customer balance: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	balance := customerBalance{CustomerID: id, Outstanding: outstanding, Invoices: invoices}
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Customer balance found", balance)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func findInvoice(w http.ResponseWriter, r *http.Request) (model.Invoice, bool) {
+	invoice := model.Invoice{}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return invoice, false
+	}
+
+	if err := db.GDB.Preload("Lines").Preload("Payments").First(&invoice, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Invoice not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return invoice, false
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return invoice, false
+	}
+
+	return invoice, true
+}
+
+func writeInvoiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response := payload.NewResponse(payload.MessageTypeError, "Customer, invoice or service not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+	case errors.Is(err, service.ErrInvoiceNegativeTotal):
+		response := payload.NewErrorResponse(payload.CodeInvoiceNegativeTotal, err.Error())
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+	case errors.Is(err, service.ErrInvoiceReference):
+		response := payload.NewErrorResponse(payload.CodeInvoiceReference, err.Error())
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+	case errors.Is(err, model.ErrMoneyOverflow):
+		response := payload.NewErrorResponse(payload.CodeAmountOverflow, err.Error())
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+	case errors.Is(err, service.ErrInvoiceNotDraft):
+		response := payload.NewErrorResponse(payload.CodeInvoiceNotDraft, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, service.ErrInvoiceNotPayable):
+		response := payload.NewErrorResponse(payload.CodeInvoiceNotPayable, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, service.ErrInvoiceNotVoidable):
+		response := payload.NewErrorResponse(payload.CodeInvoiceNotVoidable, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, service.ErrInvoiceHasPayments):
+		response := payload.NewErrorResponse(payload.CodeInvoiceHasPayments, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	case errors.Is(err, service.ErrPaymentExceedsTotal):
+		response := payload.NewErrorResponse(payload.CodePaymentExceedsTotal, err.Error())
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	default:
+		log.Printf("invoice error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/auth"
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"gorm.io/gorm"
+)
+
+type userLinksInput struct {
+	EmployeeID *uint `json:"employee_id"`
+	CustomerID *uint `json:"customer_id"`
+}
+
+func GetMe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	caller, ok := auth.CurrentUser(r)
+	if !ok {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid token", nil)
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	user := model.User{}
+	err := db.GDB.Preload("Roles").Preload("Employee.EmployeeType").Preload("Customer.Pets").
+		First(&user, caller.ID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "User not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "User found", user)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+// UpdateUserLinks links a user account to the employee and customer records it represents.
+func UpdateUserLinks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var input userLinksInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	user := model.User{}
+	if err := db.GDB.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "User not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if input.EmployeeID != nil {
+		if err := db.GDB.First(&model.Employee{}, *input.EmployeeID).Error; err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Employee was not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+	}
+
+	if input.CustomerID != nil {
+		if err := db.GDB.First(&model.Customer{}, *input.CustomerID).Error; err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Customer was not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+	}
+
+	var linked int64
+	err = db.GDB.Model(&model.User{}).
+		Where("id <> ?", user.ID).
+		Where(db.GDB.Where("employee_id = ?", input.EmployeeID).Or("customer_id = ?", input.CustomerID)).
+		Count(&linked).Error
+	if err != nil {
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if linked > 0 {
+		response := payload.NewErrorResponse(payload.CodeAlreadyLinked, "Record already linked to another user")
+		payload.ResponseJSON(w, http.StatusConflict, response)
+		return
+	}
+
+	err = db.GDB.Model(&user).Updates(map[string]interface{}{
+		"employee_id": input.EmployeeID,
+		"customer_id": input.CustomerID,
+	}).Error
+	if err != nil {
+		log.Printf("error linking user: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error saving user", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	user.EmployeeID = input.EmployeeID
+	user.CustomerID = input.CustomerID
+	response := payload.NewResponse(payload.MessageTypeSuccess, "User links updated successfully", user)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+// callerEmployeeID returns the employee linked to the authenticated account, so actions
+// are attributed to whoever performed them rather than to an id sent in the body.
+func callerEmployeeID(r *http.Request) (uint, bool) {
+	user, ok := auth.CurrentUser(r)
+	if !ok || user.EmployeeID == nil {
+		return 0, false
+	}
+	return *user.EmployeeID, true
+}
+
+// requireCallerEmployee is callerEmployeeID for actions that must be signed by an
+// employee. It answers 403 when the account is not linked to one.
+func requireCallerEmployee(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	employeeID, ok := callerEmployeeID(r)
+	if !ok {
+		response := payload.NewResponse(payload.MessageTypeError, "Your account is not linked to an employee", nil)
+		payload.ResponseJSON(w, http.StatusForbidden, response)
+		return 0, false
+	}
+	return employeeID, true
+}
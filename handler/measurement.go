@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+)
+
+func SavePetMeasurement(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	petID, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	measurement := model.PetMeasurement{}
+	if err := json.NewDecoder(r.Body).Decode(&measurement); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	// The employee is optional here, but it is never taken from the body.
+	measurement.EmployeeID = nil
+	if employeeID, ok := callerEmployeeID(r); ok {
+		measurement.EmployeeID = &employeeID
+	}
+
+	if err := service.ValidateEntity(&measurement); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if err := db.GDB.First(&model.Pet{}, petID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	measurement.PetID = petID
+	if err := service.RecordMeasurement(db.GDB, &measurement); err != nil {
+		if errors.Is(err, service.ErrEmptyMeasurement) {
+			response := payload.NewResponse(payload.MessageTypeError, err.Error(), nil)
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+
+		log.Printf("error recording measurement: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Measurement recorded successfully", measurement)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func GetPetMeasurements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	petID, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	query := db.GDB.Where("pet_id = ?", petID).Order("measured_at")
+	params := r.URL.Query()
+	if raw := params.Get("from"); raw != "" {
+		from, err := parseTimeParam(raw)
+		if err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Invalid from, expected YYYY-MM-DD or RFC 3339", nil)
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+		query = query.Where("measured_at >= ?", from)
+	}
+	if raw := params.Get("to"); raw != "" {
+		to, err := parseTimeParam(raw)
+		if err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Invalid to, expected YYYY-MM-DD or RFC 3339", nil)
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+		query = query.Where("measured_at < ?", to)
+	}
+
+	if err := db.GDB.First(&model.Pet{}, petID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	var measurements []model.PetMeasurement
+	if err := query.Find(&measurements).Error; err != nil {
+		log.Printf("error listing measurements: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(measurements)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Measurements List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	bucket := params.Get("bucket")
+	if bucket == "" {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Measurements found", measurements)
+		payload.ResponseJSON(w, http.StatusOK, response)
+		return
+	}
+
+	points, err := service.DownsampleMeasurements(measurements, bucket)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid bucket, expected day, week or month", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Measurements found", points)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+func parseUintParam(r *http.Request, name string) (uint, error) {
+	value, err := strconv.ParseUint(mux.Vars(r)[name], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return uint(value), nil
+}
+
+// parseTimeParam accepts either a local date (YYYY-MM-DD) or an RFC 3339 timestamp.
+func parseTimeParam(value string) (time.Time, error) {
+	if parsed, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return parsed, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
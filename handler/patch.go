@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/patch"
+	"github.com/IsraelTeo/api-paw-go/payload"
+)
+
+// isUpdateMethod reports whether r replaces (PUT) or partially updates (PATCH) a resource.
+func isUpdateMethod(r *http.Request) bool {
+	return r.Method == http.MethodPut || r.Method == http.MethodPatch
+}
+
+// decodeUpdateBody fills input from the request body. A PUT body is decoded as is; a
+// PATCH body is applied to current's JSON form first, so omitted fields keep their
+// stored values and the handler validates the merged result.
+func decodeUpdateBody(r *http.Request, current interface{}, input interface{}) error {
+	if r.Method != http.MethodPatch {
+		return json.NewDecoder(r.Body).Decode(input)
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+
+	patched, err := patch.Apply(r.Header.Get("Content-Type"), doc, body)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(input)
+}
+
+// writeUpdateBodyError answers a body decodeUpdateBody could not use.
+func writeUpdateBodyError(w http.ResponseWriter, err error) {
+	log.Printf("error decoding request body: %v", err)
+
+	switch {
+	case errors.Is(err, patch.ErrUnsupportedMediaType):
+		response := payload.NewResponse(payload.MessageTypeError, "Unsupported patch format, use application/merge-patch+json or application/json-patch+json", nil)
+		payload.ResponseJSON(w, http.StatusUnsupportedMediaType, response)
+	case errors.Is(err, patch.ErrTestFailed):
+		response := payload.NewErrorResponse(payload.CodePatchTestFailed, "Patch test operation failed")
+		payload.ResponseJSON(w, http.StatusConflict, response)
+	default:
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+	}
+}
@@ -31,10 +31,20 @@ func GetPetById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if notModified(w, r, entityETag(pet.ID, pet.UpdatedAt)) {
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Pet found", pet)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
+var petListSpec = service.ListSpec{
+	Filters:     map[string]string{"name": "name", "specie": "specie", "race": "race", "gender": "gender"},
+	Sorts:       map[string]string{"id": "id", "name": "name", "specie": "specie", "created_at": "created_at"},
+	DefaultSort: "id",
+}
+
 func GetAllPets(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
@@ -42,8 +52,15 @@ func GetAllPets(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var pets []model.Pet
-	if err := db.GDB.Find(&pets).Error; err != nil {
+	query, err := service.ParseListQuery(r.URL.Query(), petListSpec)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, err.Error(), nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	pets, meta, err := service.FindPage[model.Pet](db.GDB, query)
+	if err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Pets not found", nil)
 		payload.ResponseJSON(w, http.StatusNotFound, response)
 		return
@@ -56,7 +73,7 @@ func GetAllPets(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := payload.NewResponse(payload.MessageTypeSuccess, "Pets found", pets)
+	response := payload.NewPageResponse(payload.MessageTypeSuccess, "Pets found", pets, meta)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
@@ -75,14 +92,27 @@ func SavePet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := service.ValidateEntity(&pet); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if err := service.ParseBirthDate(&pet); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid birth date, expected a past date as YYYY-MM-DD", nil)
 		payload.ResponseJSON(w, http.StatusBadRequest, response)
 		return
 	}
 
-	if result := db.GDB.Create(&pet); result.Error != nil {
-		log.Printf("error creating pet: %v", result.Error)
+	err := db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&pet).Error; err != nil {
+			return err
+		}
+		if pet.Weight <= 0 {
+			return nil
+		}
+		return service.RecordMeasurement(tx, &model.PetMeasurement{PetID: pet.ID, Weight: &pet.Weight})
+	})
+	if err != nil {
+		log.Printf("error creating pet: %v", err)
 		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
@@ -93,8 +123,8 @@ func SavePet(w http.ResponseWriter, r *http.Request) {
 }
 
 func UpdatePet(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+	if !isUpdateMethod(r) {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put or patch not permit", nil)
 		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
 		return
 	}
@@ -109,14 +139,6 @@ func UpdatePet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var input model.Pet
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
-		log.Printf("error decoding request body: %v", err)
-		return
-	}
-
 	pet := model.Pet{}
 	if err := db.GDB.First(&pet, uint(id)).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -132,27 +154,58 @@ func UpdatePet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := service.ValidateEntity(&pet); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
+	if !checkIfMatch(w, r, entityETag(pet.ID, pet.UpdatedAt)) {
+		return
+	}
+	version := pet.UpdatedAt
+
+	var input model.Pet
+	if err := decodeUpdateBody(r, pet, &input); err != nil {
+		writeUpdateBodyError(w, err)
+		return
+	}
+
+	if err := service.ParseBirthDate(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid birth date, expected a past date as YYYY-MM-DD", nil)
 		payload.ResponseJSON(w, http.StatusBadRequest, response)
 		return
 	}
 
+	weightChanged := input.Weight > 0 && input.Weight != pet.Weight
 	pet.Name = input.Name
 	pet.Specie = input.Specie
 	pet.Gender = input.Gender
 	pet.Race = input.Race
-	pet.Age = input.Age
-	pet.Weight = input.Weight
+	pet.BirthDate = input.BirthDate
 
-	if err := db.GDB.Save(&pet).Error; err != nil {
+	if err := service.ValidateEntity(&pet); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	err = db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := service.SaveIfUnmodified(tx, &pet, version); err != nil {
+			return err
+		}
+		if weightChanged {
+			if err := service.RecordMeasurement(tx, &model.PetMeasurement{PetID: pet.ID, Weight: &input.Weight}); err != nil {
+				return err
+			}
+		}
+		return tx.First(&pet, pet.ID).Error
+	})
+	if errors.Is(err, service.ErrStaleEntity) {
+		writeStaleEntity(w)
+		return
+	}
+	if err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Error saving pet", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		log.Printf("error saving pet: %v", err)
 		return
 	}
 
+	w.Header().Set("ETag", entityETag(pet.ID, pet.UpdatedAt))
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Pet updated successfully", pet)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
@@ -189,7 +242,27 @@ func DeletePet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db.GDB.Delete(&pet)
+	if !checkIfMatch(w, r, entityETag(pet.ID, pet.UpdatedAt)) {
+		return
+	}
+
+	err = db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := service.DetachPetFromOwners(tx, pet.ID); err != nil {
+			return err
+		}
+		return service.DeleteIfUnmodified(tx, &pet, pet.UpdatedAt)
+	})
+	if errors.Is(err, service.ErrStaleEntity) {
+		writeStaleEntity(w)
+		return
+	}
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting pet", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error deleting pet: %v", err)
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Pet deleted successfull", nil)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
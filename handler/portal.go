@@ -0,0 +1,350 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/auth"
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+type portalAppointmentInput struct {
+	PetID      uint      `json:"pet_id" validate:"required"`
+	EmployeeID uint      `json:"employee_id" validate:"required"`
+	ServiceID  *uint     `json:"service_id"`
+	StartAt    time.Time `json:"start_at" validate:"required"`
+	EndAt      time.Time `json:"end_at"`
+	Reason     string    `json:"reason" validate:"max=255"`
+}
+
+func GetPortalPets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	customerID, ok := portalCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	customer := model.Customer{}
+	if err := db.GDB.Preload("Pets").First(&customer, customerID).Error; err != nil {
+		log.Printf("error loading portal customer: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Customer was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(customer.Pets)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Pets List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Pets found", customer.Pets)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+// portalAppointment is the customer's view of an appointment. Staff appear by name and
+// role only, and services by their public catalog fields.
+type portalAppointment struct {
+	ID       uint           `json:"id"`
+	Pet      model.Pet      `json:"pet"`
+	Employee portalEmployee `json:"employee"`
+	Service  *portalService `json:"service,omitempty"`
+	StartAt  time.Time      `json:"start_at"`
+	EndAt    time.Time      `json:"end_at"`
+	Status   string         `json:"status"`
+	Reason   string         `json:"reason"`
+}
+
+type portalEmployee struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Type      string `json:"type"`
+}
+
+type portalService struct {
+	ID              uint        `json:"id"`
+	Name            string      `json:"name"`
+	Description     string      `json:"description"`
+	Price           model.Money `json:"price"`
+	DurationMinutes uint        `json:"duration_minutes"`
+}
+
+func newPortalAppointment(appointment model.Appointment) portalAppointment {
+	view := portalAppointment{
+		ID:  appointment.ID,
+		Pet: appointment.Pet,
+		Employee: portalEmployee{
+			FirstName: appointment.Employee.FirstName,
+			LastName:  appointment.Employee.LastName,
+			Type:      appointment.Employee.EmployeeType.Name,
+		},
+		StartAt: appointment.StartAt,
+		EndAt:   appointment.EndAt,
+		Status:  appointment.Status,
+		Reason:  appointment.Reason,
+	}
+
+	if appointment.Service != nil {
+		view.Service = &portalService{
+			ID:              appointment.Service.ID,
+			Name:            appointment.Service.Name,
+			Description:     appointment.Service.Description,
+			Price:           appointment.Service.Price,
+			DurationMinutes: appointment.Service.DurationMinutes,
+		}
+	}
+	return view
+}
+
+// findPortalAppointment loads one of the customer's appointments with what
+// newPortalAppointment needs.
+func findPortalAppointment(customerID, id uint) (model.Appointment, error) {
+	appointment := model.Appointment{}
+	err := db.GDB.Preload("Pet").Preload("Employee.EmployeeType").Preload("Service").
+		Where("customer_id = ?", customerID).
+		First(&appointment, id).Error
+	return appointment, err
+}
+
+func GetPortalAppointments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	customerID, ok := portalCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	var appointments []model.Appointment
+	err := db.GDB.Preload("Pet").Preload("Employee.EmployeeType").Preload("Service").
+		Where("customer_id = ?", customerID).
+		Order("start_at DESC").
+		Find(&appointments).Error
+	if err != nil {
+		log.Printf("error listing portal appointments: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(appointments)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Appointments List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	views := make([]portalAppointment, 0, len(appointments))
+	for _, appointment := range appointments {
+		views = append(views, newPortalAppointment(appointment))
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointments found", views)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+// RequestPortalAppointment books an appointment for one of the caller's own pets. The
+// customer is always the caller; BookAppointment rejects pets they do not own.
+func RequestPortalAppointment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	customerID, ok := portalCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	input := portalAppointmentInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if !input.StartAt.After(time.Now()) {
+		response := payload.NewResponse(payload.MessageTypeError, "Appointment must start in the future", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	appointment := model.Appointment{
+		PetID:      input.PetID,
+		CustomerID: customerID,
+		EmployeeID: input.EmployeeID,
+		ServiceID:  input.ServiceID,
+		StartAt:    input.StartAt,
+		EndAt:      input.EndAt,
+		Status:     model.AppointmentStatusScheduled,
+		Reason:     input.Reason,
+	}
+	if err := service.BookAppointment(db.GDB, &appointment); err != nil {
+		writeBookingError(w, err)
+		return
+	}
+
+	booked, err := findPortalAppointment(customerID, appointment.ID)
+	if err != nil {
+		log.Printf("error loading booked appointment: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointment created successfully", newPortalAppointment(booked))
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func CancelPortalAppointment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	customerID, ok := portalCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	// Las citas de otros clientes se responden como inexistentes
+	appointment, err := findPortalAppointment(customerID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Appointment not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if appointment.Status != model.AppointmentStatusScheduled || !appointment.StartAt.After(time.Now()) {
+		response := payload.NewErrorResponse(payload.CodeInvalidStatusTransition, "Only upcoming scheduled appointments can be cancelled")
+		payload.ResponseJSON(w, http.StatusConflict, response)
+		return
+	}
+
+	appointment.Status = model.AppointmentStatusCancelled
+	if err := db.GDB.Model(&appointment).Update("status", appointment.Status).Error; err != nil {
+		log.Printf("error cancelling appointment: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error updating appointment", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Appointment cancelled successfully", newPortalAppointment(appointment))
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetPortalVaccinations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	customerID, ok := portalCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	schedule, err := service.CustomerVaccinationSchedule(db.GDB, customerID)
+	if err != nil {
+		log.Printf("error loading vaccination schedule: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(schedule)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccinations List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccination schedule found", schedule)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetPortalInvoices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	customerID, ok := portalCustomerID(w, r)
+	if !ok {
+		return
+	}
+
+	// Los borradores todavía no son facturas para el cliente
+	var invoices []model.Invoice
+	err := db.GDB.Preload("Lines").Preload("Payments").
+		Where("customer_id = ? AND status <> ?", customerID, model.InvoiceStatusDraft).
+		Order("issued_at DESC").
+		Find(&invoices).Error
+	if err != nil {
+		log.Printf("error listing portal invoices: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(invoices)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Invoices List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Invoices found", invoices)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+// portalCustomerID returns the customer linked to the caller's account. Every portal
+// query is scoped by it, never by an id taken from the request.
+func portalCustomerID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	user, ok := auth.CurrentUser(r)
+	if !ok || user.CustomerID == nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Account is not linked to a customer", nil)
+		payload.ResponseJSON(w, http.StatusForbidden, response)
+		return 0, false
+	}
+	return *user.CustomerID, true
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func GetPrescriptionById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	prescription, ok := findPrescription(w, r)
+	if !ok {
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Prescription found", prescription)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetPetPrescriptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	petID, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var prescriptions []model.Prescription
+	err = db.GDB.Preload("Employee").Preload("Product").
+		Where("pet_id = ?", petID).
+		Order("created_at DESC").
+		Find(&prescriptions).Error
+	if err != nil {
+		log.Printf("error listing prescriptions: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(prescriptions)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Prescriptions List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Prescriptions found", prescriptions)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SavePrescription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	prescription := model.Prescription{}
+	if err := json.NewDecoder(r.Body).Decode(&prescription); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employeeID, ok := requireCallerEmployee(w, r)
+	if !ok {
+		return
+	}
+	prescription.EmployeeID = employeeID
+
+	if err := service.ValidateEntity(&prescription); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if err := db.GDB.First(&model.Pet{}, prescription.PetID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	allowed, err := service.CanPrescribe(db.GDB, prescription.EmployeeID)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+	if !allowed {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee type is not allowed to prescribe", nil)
+		payload.ResponseJSON(w, http.StatusForbidden, response)
+		return
+	}
+
+	if prescription.ProductID != nil {
+		if err := db.GDB.First(&model.Product{}, *prescription.ProductID).Error; err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Product was not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+	}
+
+	prescription.ID = 0
+	prescription.Status = model.PrescriptionStatusIssued
+	prescription.FilledAt = nil
+	prescription.FilledByID = nil
+	if result := db.GDB.Omit(clause.Associations).Create(&prescription); result.Error != nil {
+		log.Printf("error creating prescription: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Prescription created successfully", prescription)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func FillPrescription(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	// Whoever dispenses the medication is the caller, never an id from the body.
+	employeeID, ok := requireCallerEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	if err := db.GDB.First(&model.Employee{}, employeeID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	prescription, err := service.FillPrescription(db.GDB, id, employeeID)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			response := payload.NewResponse(payload.MessageTypeError, "Prescription not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+		case errors.Is(err, service.ErrPrescriptionNotIssued):
+			response := payload.NewErrorResponse(payload.CodePrescriptionNotIssued, err.Error())
+			payload.ResponseJSON(w, http.StatusConflict, response)
+		case errors.Is(err, service.ErrInsufficientStock):
+			response := payload.NewErrorResponse(payload.CodeInsufficientStock, err.Error())
+			payload.ResponseJSON(w, http.StatusConflict, response)
+		default:
+			log.Printf("error filling prescription: %v", err)
+			response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+			payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		}
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Prescription filled successfully", prescription)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetPrescriptionDocument(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	prescription, ok := findPrescription(w, r)
+	if !ok {
+		return
+	}
+
+	owners, err := service.PetOwnerContacts(db.GDB, prescription.PetID)
+	if err != nil {
+		log.Printf("error loading pet owners: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=\"prescription-%d.txt\"", prescription.ID))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(service.RenderPrescription(prescription, owners))); err != nil {
+		log.Printf("error writing prescription document: %v", err)
+	}
+}
+
+func findPrescription(w http.ResponseWriter, r *http.Request) (model.Prescription, bool) {
+	prescription := model.Prescription{}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return prescription, false
+	}
+
+	err = db.GDB.Preload("Pet").Preload("Employee.EmployeeType").Preload("Product").First(&prescription, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Prescription not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return prescription, false
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return prescription, false
+	}
+
+	return prescription, true
+}
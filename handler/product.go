@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+func GetProductById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	product := model.Product{}
+	if err := db.GDB.First(&product, id).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Product was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Product found", product)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetAllProducts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	query := db.GDB.Order("name")
+
+	var products []model.Product
+	if err := query.Find(&products).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Products not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(products)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Products List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Products found", products)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveProduct(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	product := model.Product{}
+	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&product); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if exists, err := service.ValidateUniqueField("sku", product.SKU, &model.Product{}); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	} else if exists {
+		response := payload.NewErrorResponse(payload.CodeSKUTaken, "Product SKU already exists")
+		payload.ResponseJSON(w, http.StatusConflict, response)
+		return
+	}
+
+	if result := db.GDB.Create(&product); result.Error != nil {
+		log.Printf("error creating service: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Product created successfully", product)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	product := model.Product{}
+	if err := db.GDB.First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Product not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("product not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	var input model.Product
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("error decoding request body: %v", err)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if input.SKU != product.SKU {
+		if exists, err := service.ValidateUniqueField("sku", input.SKU, &model.Product{}); err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+			payload.ResponseJSON(w, http.StatusInternalServerError, response)
+			return
+		} else if exists {
+			response := payload.NewErrorResponse(payload.CodeSKUTaken, "Product SKU already exists")
+			payload.ResponseJSON(w, http.StatusConflict, response)
+			return
+		}
+	}
+
+	product.SKU = input.SKU
+	product.Name = input.Name
+	product.Unit = input.Unit
+	product.LowStockThreshold = input.LowStockThreshold
+
+	if err := db.GDB.Save(&product).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error saving product", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error saving service: %v", err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Product updated successfully", product)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response := payload.NewResponse(payload.MessageTypeError, "Method delete not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	product := model.Product{}
+	if err := db.GDB.First(&product, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Product not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("product not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	if err := db.GDB.Delete(&product).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting product", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error deleting service: %v", err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Product deleted successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
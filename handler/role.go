@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"gorm.io/gorm"
+)
+
+type userRolesInput struct {
+	Roles []string `json:"roles"`
+}
+
+type roleMFAInput struct {
+	RequireMFA bool `json:"require_mfa"`
+}
+
+func GetAllRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	var roles []model.Role
+	if err := db.GDB.Preload("Permissions").Order("name").Find(&roles).Error; err != nil {
+		log.Printf("error listing roles: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Roles found", roles)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+// UpdateUserRoles replaces the roles of a user. Changes apply to tokens issued afterwards.
+func UpdateUserRoles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var input userRolesInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	user := model.User{}
+	if err := db.GDB.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "User not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	var roles []model.Role
+	if len(input.Roles) > 0 {
+		if err := db.GDB.Where("name IN ?", input.Roles).Find(&roles).Error; err != nil {
+			log.Printf("error finding roles: %v", err)
+			response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+			payload.ResponseJSON(w, http.StatusInternalServerError, response)
+			return
+		}
+	}
+
+	if len(roles) != len(uniqueStrings(input.Roles)) {
+		response := payload.NewResponse(payload.MessageTypeError, "Unknown role", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := db.GDB.Model(&user).Association("Roles").Replace(roles); err != nil {
+		log.Printf("error updating user roles: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error saving user roles", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	user.Roles = roles
+	response := payload.NewResponse(payload.MessageTypeSuccess, "User roles updated successfully", user)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+// UpdateRoleMFA makes two-factor authentication mandatory, or optional again, for every
+// user with the role. Users without it enrolled are asked to enroll on their next login.
+func UpdateRoleMFA(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var input roleMFAInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	role := model.Role{}
+	if err := db.GDB.First(&role, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Role not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	role.RequireMFA = input.RequireMFA
+	if err := db.GDB.Model(&role).Update("require_mfa", role.RequireMFA).Error; err != nil {
+		log.Printf("error updating role: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error saving role", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Role updated successfully", role)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/search"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	minSearchLength    = 2
+)
+
+func Search(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len([]rune(query)) < minSearchLength {
+		response := payload.NewResponse(payload.MessageTypeError, "Query must have at least 2 characters", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	limit := defaultSearchLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxSearchLimit {
+			response := payload.NewResponse(payload.MessageTypeError, "Invalid limit", nil)
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+		limit = parsed
+	}
+
+	results, err := search.Default().Search(r.Context(), query, limit)
+	if err != nil {
+		log.Printf("error searching: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Search error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Search results", results)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
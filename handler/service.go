@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+func GetServiceById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	item := model.Service{}
+	if err := db.GDB.Preload("EmployeeType").First(&item, id).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Service was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Service found", item)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetAllServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	query := db.GDB.Preload("EmployeeType").Order("code")
+
+	var items []model.Service
+	if err := query.Find(&items).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Services not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(items)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Services List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Services found", items)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveService(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	item := model.Service{}
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&item); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if exists, err := service.ValidateUniqueField("code", item.Code, &model.Service{}); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	} else if exists {
+		response := payload.NewErrorResponse(payload.CodeServiceCodeTaken, "Service code already exists")
+		payload.ResponseJSON(w, http.StatusConflict, response)
+		return
+	}
+
+	if err := db.GDB.First(&model.EmployeeType{}, item.TypeID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee Type was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	if result := db.GDB.Omit("EmployeeType").Create(&item); result.Error != nil {
+		log.Printf("error creating service: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Service created successfully", item)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func UpdateService(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	item := model.Service{}
+	if err := db.GDB.First(&item, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Service not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("service not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	var input model.Service
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("error decoding request body: %v", err)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if err := db.GDB.First(&model.EmployeeType{}, input.TypeID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee Type was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	if input.Code != item.Code {
+		if exists, err := service.ValidateUniqueField("code", input.Code, &model.Service{}); err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+			payload.ResponseJSON(w, http.StatusInternalServerError, response)
+			return
+		} else if exists {
+			response := payload.NewErrorResponse(payload.CodeServiceCodeTaken, "Service code already exists")
+			payload.ResponseJSON(w, http.StatusConflict, response)
+			return
+		}
+	}
+
+	item.Code = input.Code
+	item.Name = input.Name
+	item.Description = input.Description
+	item.Price = input.Price
+	item.DurationMinutes = input.DurationMinutes
+	item.TypeID = input.TypeID
+
+	if err := db.GDB.Omit("EmployeeType").Save(&item).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error saving service", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error saving service: %v", err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Service updated successfully", item)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func DeleteService(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response := payload.NewResponse(payload.MessageTypeError, "Method delete not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	item := model.Service{}
+	if err := db.GDB.First(&item, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Service not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("service not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	if err := db.GDB.Delete(&item).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting service", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error deleting service: %v", err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Service deleted successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
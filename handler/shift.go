@@ -0,0 +1,311 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+const maxAvailabilityRange = 31 * 24 * time.Hour
+
+func GetEmployeeShifts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	employee, ok := findEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	var shifts []model.ShiftTemplate
+	if err := db.GDB.Where("employee_id = ?", employee.ID).Order("weekday").Order("start_time").Find(&shifts).Error; err != nil {
+		log.Printf("error listing shifts: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(shifts)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Shifts List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Shifts found", shifts)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveEmployeeShift(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	employee, ok := findEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	shift := model.ShiftTemplate{}
+	if err := json.NewDecoder(r.Body).Decode(&shift); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&shift); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	if err := service.ValidateShiftTemplate(&shift); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Shift must end after it starts", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	shift.ID = 0
+	shift.EmployeeID = employee.ID
+	if result := db.GDB.Create(&shift); result.Error != nil {
+		log.Printf("error creating shift: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Shift created successfully", shift)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func DeleteShift(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response := payload.NewResponse(payload.MessageTypeError, "Method delete not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	result := db.GDB.Delete(&model.ShiftTemplate{}, id)
+	if result.Error != nil {
+		log.Printf("error deleting shift: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting shift", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	if result.RowsAffected == 0 {
+		response := payload.NewResponse(payload.MessageTypeError, "Shift not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Shift deleted successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetEmployeeOverrides(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	employee, ok := findEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	var overrides []model.ShiftOverride
+	if err := db.GDB.Where("employee_id = ? AND end_at > ?", employee.ID, time.Now()).Order("start_at").Find(&overrides).Error; err != nil {
+		log.Printf("error listing shift overrides: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(overrides)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Shift overrides List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Shift overrides found", overrides)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveEmployeeOverride(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	employee, ok := findEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	override := model.ShiftOverride{}
+	if err := json.NewDecoder(r.Body).Decode(&override); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&override); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	override.ID = 0
+	override.EmployeeID = employee.ID
+	if result := db.GDB.Create(&override); result.Error != nil {
+		log.Printf("error creating shift override: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Shift override created successfully", override)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response := payload.NewResponse(payload.MessageTypeError, "Method delete not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	result := db.GDB.Delete(&model.ShiftOverride{}, id)
+	if result.Error != nil {
+		log.Printf("error deleting shift override: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting shift override", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+	if result.RowsAffected == 0 {
+		response := payload.NewResponse(payload.MessageTypeError, "Shift override not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Shift override deleted successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetEmployeeAvailability(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	query := r.URL.Query()
+	from, err := parseTimeParam(query.Get("from"))
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid from, expected YYYY-MM-DD or RFC 3339", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	to, err := parseTimeParam(query.Get("to"))
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid to, expected YYYY-MM-DD or RFC 3339", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if !to.After(from) || to.Sub(from) > maxAvailabilityRange {
+		response := payload.NewResponse(payload.MessageTypeError, "Range must be positive and at most 31 days", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	var slotLength time.Duration
+	if raw := query.Get("slot"); raw != "" {
+		minutes, err := strconv.Atoi(raw)
+		if err != nil || minutes <= 0 {
+			response := payload.NewResponse(payload.MessageTypeError, "Invalid slot, expected minutes", nil)
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+		slotLength = time.Duration(minutes) * time.Minute
+	}
+
+	employee, ok := findEmployee(w, r)
+	if !ok {
+		return
+	}
+
+	slots, err := service.EmployeeFreeSlots(db.GDB, employee.ID, from, to)
+	if err != nil {
+		log.Printf("error computing availability: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if slotLength > 0 {
+		slots = service.SplitSlots(slots, slotLength)
+	}
+	if slots == nil {
+		slots = []service.TimeSlot{}
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Availability found", slots)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func findEmployee(w http.ResponseWriter, r *http.Request) (model.Employee, bool) {
+	employee := model.Employee{}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return employee, false
+	}
+
+	if err := db.GDB.First(&employee, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Employee not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return employee, false
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return employee, false
+	}
+
+	return employee, true
+}
@@ -31,10 +31,20 @@ func GetEmployeeTypeById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if notModified(w, r, entityETag(role.ID, role.UpdatedAt)) {
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Employee Type found", role)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
+var employeeTypeListSpec = service.ListSpec{
+	Filters:     map[string]string{"name": "name"},
+	Sorts:       map[string]string{"id": "id", "name": "name"},
+	DefaultSort: "id",
+}
+
 func GetAllEmployeeTypes(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
@@ -42,8 +52,15 @@ func GetAllEmployeeTypes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var roles []model.EmployeeType
-	if err := db.GDB.Find(&roles).Error; err != nil {
+	query, err := service.ParseListQuery(r.URL.Query(), employeeTypeListSpec)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, err.Error(), nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	roles, meta, err := service.FindPage[model.EmployeeType](db.GDB, query)
+	if err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Employee Types not found", nil)
 		payload.ResponseJSON(w, http.StatusNotFound, response)
 		return
@@ -56,7 +73,7 @@ func GetAllEmployeeTypes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := payload.NewResponse(payload.MessageTypeSuccess, "Employee Types found", roles)
+	response := payload.NewPageResponse(payload.MessageTypeSuccess, "Employee Types found", roles, meta)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
@@ -75,9 +92,7 @@ func SaveEmployeeType(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := service.ValidateEntity(&role); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request.", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		service.WriteValidationError(w, err)
 		return
 	}
 
@@ -86,7 +101,7 @@ func SaveEmployeeType(w http.ResponseWriter, r *http.Request) {
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
 	} else if exists {
-		response := payload.NewResponse(payload.MessageTypeError, "Employee type already exists", nil)
+		response := payload.NewErrorResponse(payload.CodeAlreadyExists, "Employee type already exists")
 		payload.ResponseJSON(w, http.StatusConflict, response)
 		return
 	}
@@ -102,8 +117,8 @@ func SaveEmployeeType(w http.ResponseWriter, r *http.Request) {
 }
 
 func UpdateEmployeeType(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+	if !isUpdateMethod(r) {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put or patch not permit", nil)
 		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
 		return
 	}
@@ -133,24 +148,45 @@ func UpdateEmployeeType(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !checkIfMatch(w, r, entityETag(employeeType.ID, employeeType.UpdatedAt)) {
+		return
+	}
+	version := employeeType.UpdatedAt
+
 	var input model.EmployeeType
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
-		log.Printf("error decoding request body: %v", err)
+	if err := decodeUpdateBody(r, employeeType, &input); err != nil {
+		writeUpdateBodyError(w, err)
 		return
 	}
 
+	employeeType.Name = input.Name
+	employeeType.CanPrescribe = input.CanPrescribe
+
 	if err := service.ValidateEntity(&employeeType); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		service.WriteValidationError(w, err)
 		return
 	}
 
-	employeeType.Name = input.Name
+	if err := service.SaveIfUnmodified(db.GDB, &employeeType, version); err != nil {
+		if errors.Is(err, service.ErrStaleEntity) {
+			writeStaleEntity(w)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Error updating employee type", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error updating employee type: %v", err)
+		return
+	}
+
+	if err := db.GDB.First(&employeeType, employeeType.ID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error loading employee type", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error loading employee type: %v", err)
+		return
+	}
 
-	db.GDB.Save(&employeeType)
+	w.Header().Set("ETag", entityETag(employeeType.ID, employeeType.UpdatedAt))
 	response := payload.NewResponse(payload.MessageTypeSuccess, "EmployeeType updated successfull", employeeType)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
@@ -187,7 +223,22 @@ func DeleteEmployeeType(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db.GDB.Delete(&employeeType)
+	if !checkIfMatch(w, r, entityETag(employeeType.ID, employeeType.UpdatedAt)) {
+		return
+	}
+
+	if err := service.DeleteIfUnmodified(db.GDB, &employeeType, employeeType.UpdatedAt); err != nil {
+		if errors.Is(err, service.ErrStaleEntity) {
+			writeStaleEntity(w)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting employee type", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error deleting employee type: %v", err)
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "Employee type deleted successfull", nil)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/IsraelTeo/api-paw-go/auth"
 	"github.com/IsraelTeo/api-paw-go/db"
 	"github.com/IsraelTeo/api-paw-go/model"
 	"github.com/IsraelTeo/api-paw-go/payload"
@@ -16,6 +17,21 @@ import (
 	"gorm.io/gorm"
 )
 
+type registerUserInput struct {
+	Email    string `json:"email" validate:"required,email,max=100"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+type updateUserInput struct {
+	Email    string `json:"email" validate:"required,email,max=100"`
+	Password string `json:"password"`
+}
+
+type changePasswordInput struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=72"`
+}
+
 func GetUserById(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
@@ -32,10 +48,20 @@ func GetUserById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if notModified(w, r, entityETag(user.ID, user.UpdatedAt)) {
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "User found", user)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
+var userListSpec = service.ListSpec{
+	Filters:     map[string]string{"email": "email"},
+	Sorts:       map[string]string{"id": "id", "email": "email", "created_at": "created_at"},
+	DefaultSort: "id",
+}
+
 func GetAllUsers(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
@@ -43,8 +69,15 @@ func GetAllUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var users []model.User
-	if err := db.GDB.Find(&users).Error; err != nil {
+	query, err := service.ParseListQuery(r.URL.Query(), userListSpec)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, err.Error(), nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	users, meta, err := service.FindPage[model.User](db.GDB, query)
+	if err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Users not found", nil)
 		payload.ResponseJSON(w, http.StatusNotFound, response)
 		return
@@ -56,7 +89,7 @@ func GetAllUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := payload.NewResponse(payload.MessageTypeSuccess, "Users found", users)
+	response := payload.NewPageResponse(payload.MessageTypeSuccess, "Users found", users, meta)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
 
@@ -67,25 +100,27 @@ func RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user := model.User{}
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	// Solo se aceptan email y contraseña, is_admin y los roles nunca vienen del cliente
+	input := registerUserInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
 		payload.ResponseJSON(w, http.StatusBadRequest, response)
 		return
 	}
 
-	if err := service.ValidateEntity(&user); err != nil {
-		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
-		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
 		return
 	}
 
+	user := model.User{Email: input.Email, Password: input.Password}
+
 	if exists, err := service.ValidateUniqueField("email", user.Email, &model.User{}); err != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
 	} else if exists {
-		response := payload.NewResponse(payload.MessageTypeError, "Email already in use", nil)
+		response := payload.NewErrorResponse(payload.CodeEmailTaken, "Email already in use")
 		payload.ResponseJSON(w, http.StatusConflict, response)
 		return
 
@@ -105,20 +140,33 @@ func RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	customerRole := model.Role{}
+	if err := db.GDB.Where("name = ?", model.RoleCustomer).First(&customerRole).Error; err != nil {
+		log.Printf("error finding customer role: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
 	user.Password = string(hashedPassword)
-	if result := db.GDB.Create(&user); result.Error != nil {
+	user.Roles = []model.Role{customerRole}
+	if result := db.GDB.Omit("Roles.*").Create(&user); result.Error != nil {
 		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		return
 	}
 
-	response := payload.NewResponse(payload.MessageTypeSuccess, "User created successfusly", nil)
+	if err := auth.SendEmailVerification(user); err != nil {
+		log.Printf("error sending email verification: %v", err)
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "User created successfusly, check your email to verify the account", nil)
 	payload.ResponseJSON(w, http.StatusCreated, response)
 }
 
 func UpdateUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+	if !isUpdateMethod(r) {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put or patch not permit", nil)
 		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
 		return
 	}
@@ -133,6 +181,10 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canManageUser(w, r, uint(id)) {
+		return
+	}
+
 	user := model.User{}
 	if err := db.GDB.First(&user, uint(id)).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -148,30 +200,71 @@ func UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var input model.User
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
-		payload.ResponseJSON(w, http.StatusBadRequest, response)
-		log.Printf("error decoding request body: %v", err)
+	if !checkIfMatch(w, r, entityETag(user.ID, user.UpdatedAt)) {
+		return
+	}
+	version := user.UpdatedAt
+
+	var input updateUserInput
+	if err := decodeUpdateBody(r, updateUserInput{Email: user.Email}, &input); err != nil {
+		writeUpdateBodyError(w, err)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
 		return
 	}
 
-	if err := service.ValidateEntity(&user); err != nil {
-		log.Printf("validation error: %v", err)
-		response := payload.NewResponse(payload.MessageTypeError, "Bad request.", nil)
+	if input.Password != "" {
+		response := payload.NewResponse(payload.MessageTypeError, "Use the change password endpoint to update the password", nil)
 		payload.ResponseJSON(w, http.StatusBadRequest, response)
 		return
 	}
 
-	user.Email = input.Email
-	user.Password = input.Password
-	if err := db.GDB.Save(&user).Error; err != nil {
+	emailChanged := input.Email != user.Email
+	if emailChanged {
+		if exists, err := service.ValidateUniqueField("email", input.Email, &model.User{}); err != nil {
+			response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+			payload.ResponseJSON(w, http.StatusInternalServerError, response)
+			return
+		} else if exists {
+			response := payload.NewErrorResponse(payload.CodeEmailTaken, "Email already in use")
+			payload.ResponseJSON(w, http.StatusConflict, response)
+			return
+		}
+
+		user.Email = input.Email
+		user.EmailVerified = false
+		user.EmailVerifiedAt = nil
+	}
+
+	if err := service.SaveIfUnmodified(db.GDB, &user, version); err != nil {
+		if errors.Is(err, service.ErrStaleEntity) {
+			writeStaleEntity(w)
+			return
+		}
+
 		response := payload.NewResponse(payload.MessageTypeError, "Error saving user", nil)
 		payload.ResponseJSON(w, http.StatusInternalServerError, response)
 		log.Printf("error saving user: %v", err)
 		return
 	}
 
+	if err := db.GDB.First(&user, user.ID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error loading user", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error loading user: %v", err)
+		return
+	}
+
+	if emailChanged {
+		if err := auth.SendEmailVerification(user); err != nil {
+			log.Printf("error sending email verification: %v", err)
+		}
+	}
+
+	w.Header().Set("ETag", entityETag(user.ID, user.UpdatedAt))
 	response := payload.NewResponse(payload.MessageTypeSuccess, "User updated successfully", user)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
@@ -193,6 +286,10 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !canManageUser(w, r, uint(id)) {
+		return
+	}
+
 	user := model.User{}
 	if err := db.GDB.First(&user, uint(id)).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -208,7 +305,170 @@ func DeleteUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	db.GDB.Delete(&user)
+	if !checkIfMatch(w, r, entityETag(user.ID, user.UpdatedAt)) {
+		return
+	}
+
+	err = db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := auth.RevokeUserSessions(tx, user.ID); err != nil {
+			return err
+		}
+		return service.DeleteIfUnmodified(tx, &user, user.UpdatedAt)
+	})
+	if errors.Is(err, service.ErrStaleEntity) {
+		writeStaleEntity(w)
+		return
+	}
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting user", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error deleting user: %v", err)
+		return
+	}
+
 	response := payload.NewResponse(payload.MessageTypeSuccess, "User deleted successfull", nil)
 	payload.ResponseJSON(w, http.StatusOK, response)
 }
+
+// ChangePassword updates the caller's own password after checking the current one. Other
+// sessions of the account are revoked; the one making the request stays signed in.
+func ChangePassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	caller, ok := auth.CurrentUser(r)
+	if !ok {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid token", nil)
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	input := changePasswordInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	user := model.User{}
+	if err := db.GDB.First(&user, caller.ID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "User not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	if err := model.VerifyPassword(user.Password, input.CurrentPassword); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Current password is incorrect", nil)
+		payload.ResponseJSON(w, http.StatusForbidden, response)
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error hashed password", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	sessionID, err := auth.SessionID(r)
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid token", nil)
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+		return
+	}
+
+	err = db.GDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("password", string(hashedPassword)).Error; err != nil {
+			return err
+		}
+		return auth.RevokeOtherSessions(tx, user.ID, sessionID)
+	})
+	if err != nil {
+		log.Printf("error changing password: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error saving user", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Password changed successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+// canManageUser allows users to manage their own account and users:write holders to
+// manage any account.
+func canManageUser(w http.ResponseWriter, r *http.Request, id uint) bool {
+	caller, ok := auth.CurrentUser(r)
+	if !ok {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid token", nil)
+		payload.ResponseJSON(w, http.StatusUnauthorized, response)
+		return false
+	}
+
+	if caller.ID == id {
+		return true
+	}
+
+	allowed, err := auth.HasPermission(caller, "users:write")
+	if err != nil {
+		log.Printf("error checking permission: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return false
+	}
+
+	if !allowed {
+		response := payload.NewResponse(payload.MessageTypeError, "Not allowed to manage this user", nil)
+		payload.ResponseJSON(w, http.StatusForbidden, response)
+		return false
+	}
+
+	return true
+}
+
+// UnlockUser clears the login lockout of an account.
+func UnlockUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	user := model.User{}
+	if err := db.GDB.First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "User not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			return
+		}
+
+		log.Printf("database error: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	if err := auth.UnlockAccount(user.Email, user.ID); err != nil {
+		log.Printf("error unlocking user: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Error unlocking user", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "User unlocked successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
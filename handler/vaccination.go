@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm/clause"
+)
+
+const defaultDueWithinDays = 30
+
+func GetPetVaccinations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	petID, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := db.GDB.First(&model.Pet{}, petID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	var vaccinations []model.Vaccination
+	err = db.GDB.Preload("Vaccine").Preload("Employee").
+		Where("pet_id = ?", petID).
+		Order("given_at DESC").
+		Find(&vaccinations).Error
+	if err != nil {
+		log.Printf("error listing vaccinations: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(vaccinations)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccinations List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccinations found", vaccinations)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveVaccination(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	petID, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	vaccination := model.Vaccination{}
+	if err := json.NewDecoder(r.Body).Decode(&vaccination); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	employeeID, ok := requireCallerEmployee(w, r)
+	if !ok {
+		return
+	}
+	vaccination.EmployeeID = employeeID
+
+	if err := service.ValidateEntity(&vaccination); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	// The next due date is computed from this one, so a future date would push it out.
+	if vaccination.GivenAt.After(time.Now()) {
+		response := payload.NewResponse(payload.MessageTypeError, "Given date cannot be in the future", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	pet := model.Pet{}
+	if err := db.GDB.First(&pet, petID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Pet was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	vaccine := model.Vaccine{}
+	if err := db.GDB.First(&vaccine, vaccination.VaccineID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Vaccine was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	if !strings.EqualFold(vaccine.Specie, pet.Specie) {
+		response := payload.NewResponse(payload.MessageTypeError, "Vaccine is not meant for this specie", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := db.GDB.First(&model.Employee{}, vaccination.EmployeeID).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Employee was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	vaccination.PetID = pet.ID
+	vaccination.NextDueAt = service.NextVaccinationDue(vaccination.GivenAt, vaccine)
+	if result := db.GDB.Omit(clause.Associations).Create(&vaccination); result.Error != nil {
+		log.Printf("error creating vaccination: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccination created successfully", vaccination)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func GetDueVaccinations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	days := defaultDueWithinDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			response := payload.NewResponse(payload.MessageTypeError, "Invalid days, expected a non-negative number", nil)
+			payload.ResponseJSON(w, http.StatusBadRequest, response)
+			return
+		}
+		days = parsed
+	}
+
+	due, err := service.DueVaccinations(db.GDB, time.Now().AddDate(0, 0, days))
+	if err != nil {
+		log.Printf("error listing due vaccinations: %v", err)
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(due)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "No vaccinations due", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Due vaccinations found", due)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
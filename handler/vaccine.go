@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/model"
+	"github.com/IsraelTeo/api-paw-go/payload"
+	"github.com/IsraelTeo/api-paw-go/service"
+	"gorm.io/gorm"
+)
+
+func GetVaccineById(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid Method", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	vaccine := model.Vaccine{}
+	if err := db.GDB.First(&vaccine, id).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Vaccine was not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccine found", vaccine)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func GetAllVaccines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		response := payload.NewResponse(payload.MessageTypeError, "Method get not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	query := db.GDB.Order("specie").Order("name")
+	if specie := r.URL.Query().Get("specie"); specie != "" {
+		query = query.Where("specie = ?", specie)
+	}
+
+	var vaccines []model.Vaccine
+	if err := query.Find(&vaccines).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Vaccines not found", nil)
+		payload.ResponseJSON(w, http.StatusNotFound, response)
+		return
+	}
+
+	empty := service.VerifyListEmpty(vaccines)
+	if empty {
+		response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccines List empty", nil)
+		payload.ResponseJSON(w, http.StatusNoContent, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccines found", vaccines)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func SaveVaccine(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response := payload.NewResponse(payload.MessageTypeError, "Method post not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	vaccine := model.Vaccine{}
+	if err := json.NewDecoder(r.Body).Decode(&vaccine); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request: invalid JSON data", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	if err := service.ValidateEntity(&vaccine); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	var count int64
+	if err := db.GDB.Model(&model.Vaccine{}).Where("name = ? AND specie = ?", vaccine.Name, vaccine.Specie).Count(&count).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	} else if count > 0 {
+		response := payload.NewErrorResponse(payload.CodeAlreadyExists, "Vaccine already exists for this specie")
+		payload.ResponseJSON(w, http.StatusConflict, response)
+		return
+	}
+
+	if result := db.GDB.Create(&vaccine); result.Error != nil {
+		log.Printf("error creating vaccine: %v", result.Error)
+		response := payload.NewResponse(payload.MessageTypeError, "Internal Server Error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccine created successfully", vaccine)
+	payload.ResponseJSON(w, http.StatusCreated, response)
+}
+
+func UpdateVaccine(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		response := payload.NewResponse(payload.MessageTypeError, "Method put not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	vaccine := model.Vaccine{}
+	if err := db.GDB.First(&vaccine, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Vaccine not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("vaccine not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	var input model.Vaccine
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid request body", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("error decoding request body: %v", err)
+		return
+	}
+
+	if err := service.ValidateEntity(&input); err != nil {
+		service.WriteValidationError(w, err)
+		return
+	}
+
+	vaccine.Name = input.Name
+	vaccine.Specie = input.Specie
+	vaccine.BoosterIntervalDays = input.BoosterIntervalDays
+
+	if err := db.GDB.Save(&vaccine).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error saving vaccine", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error saving vaccine: %v", err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccine updated successfully", vaccine)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
+
+func DeleteVaccine(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		response := payload.NewResponse(payload.MessageTypeError, "Method delete not permit", nil)
+		payload.ResponseJSON(w, http.StatusMethodNotAllowed, response)
+		return
+	}
+
+	id, err := parseUintParam(r, "id")
+	if err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Invalid ID format", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		log.Printf("invalid ID format: %v", err)
+		return
+	}
+
+	vaccine := model.Vaccine{}
+	if err := db.GDB.First(&vaccine, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response := payload.NewResponse(payload.MessageTypeError, "Vaccine not found", nil)
+			payload.ResponseJSON(w, http.StatusNotFound, response)
+			log.Printf("vaccine not found: %v", err)
+			return
+		}
+
+		response := payload.NewResponse(payload.MessageTypeError, "Database error", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("database error: %v", err)
+		return
+	}
+
+	if err := db.GDB.Delete(&vaccine).Error; err != nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Error deleting vaccine", nil)
+		payload.ResponseJSON(w, http.StatusInternalServerError, response)
+		log.Printf("error deleting vaccine: %v", err)
+		return
+	}
+
+	response := payload.NewResponse(payload.MessageTypeSuccess, "Vaccine deleted successfully", nil)
+	payload.ResponseJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,28 @@
+package mail
+
+import "testing"
+
+func TestMemoryMailerKeepsMessages(t *testing.T) {
+	mailer := NewMemoryMailer()
+	previous := Default()
+	SetDefault(mailer)
+	defer SetDefault(previous)
+
+	if err := Send(Message{To: "a@example.com", Subject: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Send(Message{To: "b@example.com", Subject: "second"}); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := mailer.Messages()
+	if len(messages) != 2 || messages[0].Subject != "first" || messages[1].To != "b@example.com" {
+		t.Fatalf("unexpected messages: %+v", messages)
+	}
+
+	// Messages returns a copy, so callers cannot rewrite what was sent.
+	messages[0].Subject = "changed"
+	if mailer.Messages()[0].Subject != "first" {
+		t.Error("Messages exposed the mailer's internal slice")
+	}
+}
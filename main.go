@@ -4,9 +4,12 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/IsraelTeo/api-paw-go/auth"
 	"github.com/IsraelTeo/api-paw-go/config"
 	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/mail"
 	"github.com/IsraelTeo/api-paw-go/route"
+	"github.com/IsraelTeo/api-paw-go/search"
 	"github.com/IsraelTeo/api-paw-go/service"
 	"github.com/joho/godotenv"
 )
@@ -21,10 +24,19 @@ func main() {
 		log.Fatal("Error loanding .env main")
 	}
 
+	if err := auth.InitKeys(); err != nil {
+		log.Fatalf("Error loading JWT signing keys: %v", err)
+	}
+
+	mail.Init()
+	auth.InitLoginLimiter(auth.NewMemoryAttemptStore())
+
 	if err := db.Connection(); err != nil {
 		log.Fatalf("Error trying to connect with database: %v", err)
 	}
 
+	search.SetDefault(search.NewMySQLIndex(db.GDB))
+
 	if err := db.MigrateDataBase(); err != nil {
 		log.Fatalf("Error migrating database: %v", err)
 	}
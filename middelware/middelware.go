@@ -17,17 +17,18 @@ func Log(f func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWri
 
 func ValidateJWT(f func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
-		_, err := auth.ValidateToken(r)
+		userData, err := auth.ValidateToken(r)
 		if err != nil {
 			response := payload.NewResponse(payload.MessageTypeError, "Invalid token.", nil)
 			payload.ResponseJSON(w, http.StatusUnauthorized, response)
 			return
 		}
-		f(w, r)
+		f(w, r.WithContext(auth.WithUser(r.Context(), userData)))
 	}
 }
 
-func ValidateJWTAdmin(f func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+// RequirePermission validates the token and checks that one of the user's roles grants permission.
+func RequirePermission(permission string, f func(w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		userData, err := auth.ValidateToken(r)
 		if err != nil {
@@ -36,12 +37,20 @@ func ValidateJWTAdmin(f func(w http.ResponseWriter, r *http.Request)) func(w htt
 			return
 		}
 
-		if !userData.IsAdmin {
-			response := payload.NewResponse(payload.MessageTypeError, "Not admin", nil)
+		allowed, err := auth.HasPermission(userData, permission)
+		if err != nil {
+			log.Printf("error checking permission %s: %v", permission, err)
+			response := payload.NewResponse(payload.MessageTypeError, "Internal server error", nil)
+			payload.ResponseJSON(w, http.StatusInternalServerError, response)
+			return
+		}
+
+		if !allowed {
+			response := payload.NewResponse(payload.MessageTypeError, "Missing permission "+permission, nil)
 			payload.ResponseJSON(w, http.StatusForbidden, response)
 			return
 		}
 
-		f(w, r)
+		f(w, r.WithContext(auth.WithUser(r.Context(), userData)))
 	}
 }
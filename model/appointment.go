@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AppointmentStatusScheduled = "scheduled"
+	AppointmentStatusCheckedIn = "checked-in"
+	AppointmentStatusCompleted = "completed"
+	AppointmentStatusCancelled = "cancelled"
+	AppointmentStatusNoShow    = "no-show"
+)
+
+type Appointment struct {
+	gorm.Model
+	PetID      uint      `json:"pet_id" gorm:"index;not null" validate:"required"`
+	Pet        Pet       `json:"pet" gorm:"foreignKey:PetID" validate:"-"`
+	CustomerID uint      `json:"customer_id" gorm:"index;not null" validate:"required"`
+	Customer   Customer  `json:"customer" gorm:"foreignKey:CustomerID" validate:"-"`
+	EmployeeID uint      `json:"employee_id" gorm:"index;not null" validate:"required"`
+	Employee   Employee  `json:"employee" gorm:"foreignKey:EmployeeID" validate:"-"`
+	StartAt    time.Time `json:"start_at" gorm:"index;not null" validate:"required"`
+	EndAt      time.Time `json:"end_at" gorm:"index;not null"`
+	ServiceID  *uint     `json:"service_id" gorm:"index"`
+	Service    *Service  `json:"service,omitempty" gorm:"foreignKey:ServiceID" validate:"-"`
+	Status     string    `json:"status" gorm:"size:20;not null;default:scheduled"`
+	Reason     string    `json:"reason" gorm:"size:255" validate:"max=255"`
+}
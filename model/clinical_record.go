@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ClinicalRecordVisitNote  = "visit_note"
+	ClinicalRecordDiagnosis  = "diagnosis"
+	ClinicalRecordTreatment  = "treatment"
+	ClinicalRecordVitalSigns = "vital_signs"
+)
+
+var ErrClinicalRecordImmutable = errors.New("clinical records are append-only")
+
+// ClinicalRecord groups every version of a single clinical entry for a pet.
+// Corrections never modify an existing row: they append a new ClinicalRecordVersion.
+type ClinicalRecord struct {
+	ID        uint                    `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time               `json:"created_at"`
+	PetID     uint                    `json:"pet_id" gorm:"index;not null"`
+	Kind      string                  `json:"kind" gorm:"size:20;not null"`
+	Current   *ClinicalRecordVersion  `json:"current,omitempty" gorm:"-"`
+	Versions  []ClinicalRecordVersion `json:"versions,omitempty" gorm:"foreignKey:RecordID"`
+}
+
+type ClinicalRecordVersion struct {
+	ID               uint      `json:"id" gorm:"primarykey"`
+	CreatedAt        time.Time `json:"created_at"`
+	RecordID         uint      `json:"record_id" gorm:"not null;uniqueIndex:idx_record_version"`
+	Version          uint      `json:"version" gorm:"not null;uniqueIndex:idx_record_version"`
+	EmployeeID       uint      `json:"employee_id" gorm:"index;not null" validate:"required"`
+	Employee         Employee  `json:"employee" gorm:"foreignKey:EmployeeID" validate:"-"`
+	Notes            string    `json:"notes" gorm:"type:text"`
+	Diagnosis        string    `json:"diagnosis" gorm:"type:text"`
+	Treatment        string    `json:"treatment" gorm:"type:text"`
+	Temperature      *float64  `json:"temperature" validate:"omitempty,gt=0,lt=50"`
+	HeartRate        *uint     `json:"heart_rate" validate:"omitempty,gt=0,lt=400"`
+	RespiratoryRate  *uint     `json:"respiratory_rate" validate:"omitempty,gt=0,lt=200"`
+	Weight           *float64  `json:"weight" validate:"omitempty,gt=0"`
+	CorrectionReason string    `json:"correction_reason" gorm:"size:255" validate:"max=255"`
+}
+
+func (ClinicalRecord) BeforeUpdate(*gorm.DB) error {
+	return ErrClinicalRecordImmutable
+}
+
+func (ClinicalRecord) BeforeDelete(*gorm.DB) error {
+	return ErrClinicalRecordImmutable
+}
+
+func (ClinicalRecordVersion) BeforeUpdate(*gorm.DB) error {
+	return ErrClinicalRecordImmutable
+}
+
+func (ClinicalRecordVersion) BeforeDelete(*gorm.DB) error {
+	return ErrClinicalRecordImmutable
+}
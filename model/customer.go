@@ -9,6 +9,6 @@ type Customer struct {
 	DNI         string `json:"dni" gorm:"size:15;unique;not null"`
 	Email       string `json:"email" gorm:"size:100;unique;not null"`
 	PhoneNumber string `json:"phone_number" gorm:"unique;size:15"`
-	PetID       uint   `json:"pet_id" gorm:"index" validate:"required"`
-	Pet         Pet    `json:"-" gorm:"foreignKey:PetID;constraint:OnDelete:CASCADE"`
+	PetIDs      []uint `json:"pet_ids,omitempty" gorm:"-"`
+	Pets        []Pet  `json:"pets" gorm:"many2many:customer_pets" validate:"-"`
 }
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	StockMovementIn       = "in"
+	StockMovementOut      = "out"
+	StockMovementDispense = "dispense"
+)
+
+var ErrStockMovementImmutable = errors.New("stock movements are append-only")
+
+type Product struct {
+	gorm.Model
+	SKU               string `json:"sku" gorm:"size:30;unique;not null" validate:"required,max=30"`
+	Name              string `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
+	Unit              string `json:"unit" gorm:"size:20;not null" validate:"required,max=20"`
+	LowStockThreshold int64  `json:"low_stock_threshold" validate:"min=0"`
+}
+
+type Batch struct {
+	gorm.Model
+	ProductID uint      `json:"product_id" gorm:"index;not null"`
+	LotNumber string    `json:"lot_number" gorm:"size:50;not null" validate:"required,max=50"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index;not null" validate:"required"`
+	Quantity  int64     `json:"quantity" gorm:"not null" validate:"gt=0"`
+}
+
+// StockMovement is the ledger of every stock change. Quantity is positive for stock-in
+// and negative for stock-out, so summing a batch's movements gives its current quantity.
+type StockMovement struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	ProductID  uint      `json:"product_id" gorm:"index;not null"`
+	BatchID    uint      `json:"batch_id" gorm:"index;not null"`
+	Kind       string    `json:"kind" gorm:"size:10;not null"`
+	Quantity   int64     `json:"quantity" gorm:"not null"`
+	PetID      *uint     `json:"pet_id" gorm:"index"`
+	EmployeeID *uint     `json:"employee_id" gorm:"index"`
+	Reason     string    `json:"reason" gorm:"size:255"`
+}
+
+func (StockMovement) BeforeUpdate(*gorm.DB) error {
+	return ErrStockMovementImmutable
+}
+
+func (StockMovement) BeforeDelete(*gorm.DB) error {
+	return ErrStockMovementImmutable
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	InvoiceStatusDraft  = "draft"
+	InvoiceStatusIssued = "issued"
+	InvoiceStatusPaid   = "paid"
+	InvoiceStatusVoid   = "void"
+)
+
+type Invoice struct {
+	gorm.Model
+	Number        *string       `json:"number" gorm:"size:20;unique"`
+	CustomerID    uint          `json:"customer_id" gorm:"index;not null" validate:"required"`
+	Customer      Customer      `json:"-" gorm:"foreignKey:CustomerID" validate:"-"`
+	AppointmentID *uint         `json:"appointment_id" gorm:"index"`
+	PetID         *uint         `json:"pet_id" gorm:"index"`
+	Status        string        `json:"status" gorm:"size:10;not null;default:draft"`
+	TaxRateBps    uint          `json:"tax_rate_bps" validate:"max=10000"`
+	Discount      Money         `json:"discount" validate:"min=0"`
+	Subtotal      Money         `json:"subtotal"`
+	DiscountTotal Money         `json:"discount_total"`
+	TaxTotal      Money         `json:"tax_total"`
+	Total         Money         `json:"total"`
+	AmountPaid    Money         `json:"amount_paid"`
+	IssuedAt      *time.Time    `json:"issued_at"`
+	PaidAt        *time.Time    `json:"paid_at"`
+	VoidedAt      *time.Time    `json:"voided_at"`
+	Lines         []InvoiceLine `json:"lines" gorm:"foreignKey:InvoiceID" validate:"required,min=1,dive"`
+	Payments      []Payment     `json:"payments" gorm:"foreignKey:InvoiceID" validate:"-"`
+}
+
+type InvoiceLine struct {
+	ID          uint   `json:"id" gorm:"primarykey"`
+	InvoiceID   uint   `json:"invoice_id" gorm:"index;not null"`
+	PetID       *uint  `json:"pet_id" gorm:"index"`
+	ServiceID   *uint  `json:"service_id" gorm:"index"`
+	Description string `json:"description" gorm:"size:255;not null" validate:"required_without=ServiceID,max=255"`
+	Quantity    uint   `json:"quantity" gorm:"not null" validate:"required,gt=0"`
+	UnitPrice   Money  `json:"unit_price" gorm:"not null" validate:"min=0"`
+	Discount    Money  `json:"discount" validate:"min=0"`
+	Total       Money  `json:"total"`
+}
+
+type Payment struct {
+	gorm.Model
+	InvoiceID uint      `json:"invoice_id" gorm:"index;not null"`
+	Amount    Money     `json:"amount" gorm:"not null" validate:"gt=0"`
+	Method    string    `json:"method" gorm:"size:20;not null" validate:"required,oneof=cash card transfer"`
+	Reference string    `json:"reference" gorm:"size:100" validate:"max=100"`
+	PaidAt    time.Time `json:"paid_at"`
+}
+
+func (i Invoice) Balance() Money {
+	return i.Total - i.AmountPaid
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount in cents. Keeping amounts as integers makes invoice arithmetic exact,
+// which float64 cannot guarantee. It is encoded in JSON as a decimal string such as "12.50".
+type Money int64
+
+var ErrMoneyOverflow = errors.New("amount is too large")
+
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" || len(fraction) > 2 {
+		return 0, fmt.Errorf("invalid amount %q: expected at most two decimals", s)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	units, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/100-1 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	cents, err := strconv.ParseUint(fraction, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+
+	amount := Money(units*100 + cents)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// Add returns m + other, or ErrMoneyOverflow when the sum does not fit in an int64.
+func (m Money) Add(other Money) (Money, error) {
+	sum := m + other
+	if (other > 0 && sum < m) || (other < 0 && sum > m) {
+		return 0, ErrMoneyOverflow
+	}
+	return sum, nil
+}
+
+// Mul returns m * factor, or ErrMoneyOverflow when the product does not fit in an int64.
+func (m Money) Mul(factor int64) (Money, error) {
+	if m == 0 || factor == 0 {
+		return 0, nil
+	}
+
+	product := m * Money(factor)
+	if (factor == -1 && m == math.MinInt64) || product/Money(factor) != m {
+		return 0, ErrMoneyOverflow
+	}
+	return product, nil
+}
+
+func (m Money) String() string {
+	sign := ""
+	value := int64(m)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	amount, err := ParseMoney(string(bytes.Trim(data, `"`)))
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
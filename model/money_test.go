@@ -0,0 +1,79 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{input: "0", want: 0},
+		{input: "12", want: 1200},
+		{input: "12.5", want: 1250},
+		{input: "12.50", want: 1250},
+		{input: "0.01", want: 1},
+		{input: " 3.07 ", want: 307},
+		{input: "-4.20", want: -420},
+		{input: "12.", want: 1200},
+		{input: "12.345", wantErr: true},
+		{input: ".50", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "1.x", wantErr: true},
+		{input: "92233720368547758.07", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %v, want an error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSONRoundTrip(t *testing.T) {
+	for _, amount := range []Money{0, 5, 1250, -99, 100000} {
+		data, err := amount.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON(%d): %v", amount, err)
+		}
+
+		var decoded Money
+		if err := decoded.UnmarshalJSON(data); err != nil {
+			t.Fatalf("UnmarshalJSON(%s): %v", data, err)
+		}
+		if decoded != amount {
+			t.Errorf("round trip of %d gave %d via %s", amount, decoded, data)
+		}
+	}
+}
+
+func TestMoneyOverflow(t *testing.T) {
+	if _, err := Money(math.MaxInt64).Add(1); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("MaxInt64 + 1: got %v, want ErrMoneyOverflow", err)
+	}
+	if _, err := Money(math.MaxInt64 / 2).Mul(3); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("MaxInt64/2 * 3: got %v, want ErrMoneyOverflow", err)
+	}
+	if _, err := Money(math.MinInt64).Mul(-1); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("MinInt64 * -1: got %v, want ErrMoneyOverflow", err)
+	}
+	if got, err := Money(1250).Mul(3); err != nil || got != 3750 {
+		t.Errorf("1250 * 3 = %d, %v; want 3750", got, err)
+	}
+}
@@ -1,13 +1,52 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Pet struct {
 	gorm.Model
-	Name   string  `json:"name" gorm:"size:70"`
-	Specie string  `json:"specie" gorm:"size:50"`
-	Gender string  `json:"gender" gorm:"size:10"`
-	Race   string  `json:"race" gorm:"size:50"`
-	Age    uint    `json:"age"`
-	Weight float64 `json:"weight"`
+	Name         string     `json:"name" gorm:"size:70"`
+	Specie       string     `json:"specie" gorm:"size:50"`
+	Gender       string     `json:"gender" gorm:"size:10"`
+	Race         string     `json:"race" gorm:"size:50"`
+	BirthDate    *time.Time `json:"-" gorm:"type:date"`
+	BirthDateRaw string     `json:"birth_date" gorm:"-"`
+	Age          uint       `json:"age" gorm:"-"`
+	Weight       float64    `json:"weight"`
+}
+
+// AfterFind derives the age in whole years from the stored birth date.
+func (p *Pet) AfterFind(*gorm.DB) error {
+	if p.BirthDate == nil {
+		return nil
+	}
+
+	p.BirthDateRaw = p.BirthDate.Format("2006-01-02")
+	p.Age = ageInYears(*p.BirthDate, time.Now())
+	return nil
+}
+
+func ageInYears(birth, now time.Time) uint {
+	years := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		years--
+	}
+	if years < 0 {
+		return 0
+	}
+	return uint(years)
+}
+
+type PetMeasurement struct {
+	ID          uint      `json:"id" gorm:"primarykey"`
+	CreatedAt   time.Time `json:"created_at"`
+	PetID       uint      `json:"pet_id" gorm:"index:idx_pet_measured_at;not null"`
+	MeasuredAt  time.Time `json:"measured_at" gorm:"index:idx_pet_measured_at;not null"`
+	Weight      *float64  `json:"weight" validate:"omitempty,gt=0"`
+	Temperature *float64  `json:"temperature" validate:"omitempty,gt=0,lt=50"`
+	HeartRate   *uint     `json:"heart_rate" validate:"omitempty,gt=0,lt=400"`
+	EmployeeID  *uint     `json:"employee_id" gorm:"index"`
 }
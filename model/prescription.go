@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	PrescriptionStatusIssued = "issued"
+	PrescriptionStatusFilled = "filled"
+)
+
+type Prescription struct {
+	gorm.Model
+	PetID        uint       `json:"pet_id" gorm:"index;not null" validate:"required"`
+	Pet          Pet        `json:"pet" gorm:"foreignKey:PetID" validate:"-"`
+	EmployeeID   uint       `json:"employee_id" gorm:"index;not null" validate:"required"`
+	Employee     Employee   `json:"employee" gorm:"foreignKey:EmployeeID" validate:"-"`
+	ProductID    *uint      `json:"product_id" gorm:"index"`
+	Product      *Product   `json:"product,omitempty" gorm:"foreignKey:ProductID" validate:"-"`
+	Drug         string     `json:"drug" gorm:"size:100;not null" validate:"required,max=100"`
+	Dosage       string     `json:"dosage" gorm:"size:100;not null" validate:"required,max=100"`
+	Frequency    string     `json:"frequency" gorm:"size:100;not null" validate:"required,max=100"`
+	DurationDays uint       `json:"duration_days" gorm:"not null" validate:"required,gt=0"`
+	Instructions string     `json:"instructions" gorm:"type:text"`
+	Quantity     int64      `json:"quantity" validate:"min=0"`
+	Status       string     `json:"status" gorm:"size:10;not null;default:issued"`
+	FilledAt     *time.Time `json:"filled_at"`
+	FilledByID   *uint      `json:"filled_by_id"`
+}
@@ -0,0 +1,22 @@
+package model
+
+const (
+	RoleAdmin        = "admin"
+	RoleVet          = "vet"
+	RoleReceptionist = "receptionist"
+	RoleGroomer      = "groomer"
+	RoleCustomer     = "customer"
+)
+
+type Role struct {
+	ID          uint         `json:"id" gorm:"primarykey"`
+	Name        string       `json:"name" gorm:"size:30;unique;not null"`
+	RequireMFA  bool         `json:"require_mfa" gorm:"default:false"`
+	Permissions []Permission `json:"permissions,omitempty" gorm:"many2many:role_permissions" validate:"-"`
+}
+
+// Permission names a resource and an action, e.g. "pets:write".
+type Permission struct {
+	ID   uint   `json:"id" gorm:"primarykey"`
+	Name string `json:"name" gorm:"size:50;unique;not null"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "gorm.io/gorm"
+
+type Service struct {
+	gorm.Model
+	Code            string       `json:"code" gorm:"size:20;unique;not null" validate:"required,max=20"`
+	Name            string       `json:"name" gorm:"size:100;not null" validate:"required,max=100"`
+	Description     string       `json:"description" gorm:"size:255" validate:"max=255"`
+	Price           Money        `json:"price" gorm:"not null" validate:"min=0"`
+	DurationMinutes uint         `json:"duration_minutes" gorm:"not null" validate:"required,gt=0"`
+	TypeID          uint         `json:"type_id" gorm:"index;not null" validate:"required"`
+	EmployeeType    EmployeeType `json:"employee_type" gorm:"foreignKey:TypeID" validate:"-"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session is one login of a user. Every refresh token issued for that login belongs to
+// the same session, so revoking the session revokes the whole refresh token family.
+type Session struct {
+	gorm.Model
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time  `json:"created_at"`
+	SessionID uint       `json:"session_id" gorm:"index;not null"`
+	TokenHash string     `json:"-" gorm:"size:64;unique;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	RotatedAt *time.Time `json:"rotated_at"`
+}
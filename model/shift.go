@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ShiftOverrideVacation    = "vacation"
+	ShiftOverrideSickLeave   = "sick_leave"
+	ShiftOverrideUnavailable = "unavailable"
+	ShiftOverrideExtraShift  = "extra_shift"
+)
+
+// ShiftTemplate is a recurring weekly shift. Weekday follows time.Weekday (0 is Sunday)
+// and StartTime/EndTime are local wall-clock times formatted as HH:MM.
+type ShiftTemplate struct {
+	gorm.Model
+	EmployeeID uint   `json:"employee_id" gorm:"index;not null"`
+	Weekday    int    `json:"weekday" gorm:"not null" validate:"min=0,max=6"`
+	StartTime  string `json:"start_time" gorm:"size:5;not null" validate:"required,datetime=15:04"`
+	EndTime    string `json:"end_time" gorm:"size:5;not null" validate:"required,datetime=15:04"`
+}
+
+// ShiftOverride is a one-off change to an employee's schedule. An extra shift adds
+// working time; every other kind removes it.
+type ShiftOverride struct {
+	gorm.Model
+	EmployeeID uint      `json:"employee_id" gorm:"index;not null"`
+	Kind       string    `json:"kind" gorm:"size:20;not null" validate:"required,oneof=vacation sick_leave unavailable extra_shift"`
+	StartAt    time.Time `json:"start_at" gorm:"index;not null" validate:"required"`
+	EndAt      time.Time `json:"end_at" gorm:"index;not null" validate:"required,gtfield=StartAt"`
+	Reason     string    `json:"reason" gorm:"size:255" validate:"max=255"`
+}
@@ -4,5 +4,6 @@ import "gorm.io/gorm"
 
 type EmployeeType struct {
 	gorm.Model
-	Name string `json:"name" gorm:"unique;not null;size:20"`
+	Name         string `json:"name" gorm:"unique;not null;size:20"`
+	CanPrescribe bool   `json:"can_prescribe" gorm:"default:false"`
 }
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -8,8 +10,48 @@ import (
 type User struct {
 	gorm.Model
 	Email    string `json:"email" gorm:"size:100;unique;not_null"`
-	Password string `json:"password" gorm:"size:100"`
+	Password string `json:"-" gorm:"size:100"`
 	IsAdmin  bool   `json:"is_admin" gorm:"dafault:false"`
+
+	EmailVerified   bool       `json:"email_verified" gorm:"default:false"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+
+	Roles []Role `json:"roles,omitempty" gorm:"many2many:user_roles" validate:"-"`
+
+	EmployeeID *uint     `json:"employee_id" gorm:"uniqueIndex"`
+	Employee   *Employee `json:"employee,omitempty" gorm:"foreignKey:EmployeeID" validate:"-"`
+	CustomerID *uint     `json:"customer_id" gorm:"uniqueIndex"`
+	Customer   *Customer `json:"customer,omitempty" gorm:"foreignKey:CustomerID" validate:"-"`
+
+	TOTPSecret   string `json:"-" gorm:"size:64"`
+	TOTPEnabled  bool   `json:"totp_enabled" gorm:"default:false"`
+	TOTPLastStep int64  `json:"-" gorm:"default:0"`
+}
+
+// RecoveryCode is a single-use fallback for a lost authenticator. Only its hash is stored.
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time  `json:"created_at"`
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null"`
+	UsedAt    *time.Time `json:"used_at"`
+}
+
+func (u User) HasRole(name string) bool {
+	for _, role := range u.Roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (u User) RoleNames() []string {
+	names := make([]string, 0, len(u.Roles))
+	for _, role := range u.Roles {
+		names = append(names, role.Name)
+	}
+	return names
 }
 
 func VerifyPassword(passwordHashed string, password string) error {
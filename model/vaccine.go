@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Vaccine struct {
+	gorm.Model
+	Name                string `json:"name" gorm:"size:100;not null;uniqueIndex:idx_vaccine_specie" validate:"required,max=100"`
+	Specie              string `json:"specie" gorm:"size:50;not null;uniqueIndex:idx_vaccine_specie" validate:"required,max=50"`
+	BoosterIntervalDays uint   `json:"booster_interval_days" gorm:"not null" validate:"required,gt=0"`
+}
+
+type Vaccination struct {
+	gorm.Model
+	PetID      uint      `json:"pet_id" gorm:"index;not null"`
+	Pet        Pet       `json:"pet" gorm:"foreignKey:PetID" validate:"-"`
+	VaccineID  uint      `json:"vaccine_id" gorm:"index;not null" validate:"required"`
+	Vaccine    Vaccine   `json:"vaccine" gorm:"foreignKey:VaccineID" validate:"-"`
+	EmployeeID uint      `json:"employee_id" gorm:"index;not null" validate:"required"`
+	Employee   Employee  `json:"employee" gorm:"foreignKey:EmployeeID" validate:"-"`
+	LotNumber  string    `json:"lot_number" gorm:"size:50;not null" validate:"required,max=50"`
+	GivenAt    time.Time `json:"given_at" gorm:"not null" validate:"required"`
+	NextDueAt  time.Time `json:"next_due_at" gorm:"index;not null"`
+}
@@ -0,0 +1,100 @@
+package payload
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+const (
+	ContentTypeProblem   = "application/problem+json"
+	CodeValidationFailed = "validation_failed"
+)
+
+// Problem codes for domain errors. They are part of the API contract: rename one and
+// clients switching on it break.
+const (
+	CodeStaleEntity             = "stale_entity"
+	CodeAlreadyExists           = "already_exists"
+	CodeAlreadyLinked           = "already_linked"
+	CodeEmailTaken              = "email_taken"
+	CodeDNITaken                = "dni_taken"
+	CodePhoneNumberTaken        = "phone_number_taken"
+	CodeSKUTaken                = "sku_taken"
+	CodeServiceCodeTaken        = "service_code_taken"
+	CodeInvalidTimeSlot         = "invalid_time_slot"
+	CodePetNotOwned             = "pet_not_owned"
+	CodeEmployeeNotQualified    = "employee_not_qualified"
+	CodeEmployeeDoubleBooked    = "employee_double_booked"
+	CodePetDoubleBooked         = "pet_double_booked"
+	CodeEmployeeUnavailable     = "employee_unavailable"
+	CodeInvalidStatusTransition = "invalid_status_transition"
+	CodeInsufficientStock       = "insufficient_stock"
+	CodePrescriptionNotIssued   = "prescription_not_issued"
+	CodeInvoiceNotDraft         = "invoice_not_draft"
+	CodeInvoiceNotPayable       = "invoice_not_payable"
+	CodeInvoiceNotVoidable      = "invoice_not_voidable"
+	CodeInvoiceHasPayments      = "invoice_has_payments"
+	CodePaymentExceedsTotal     = "payment_exceeds_total"
+	CodeInvoiceNegativeTotal    = "invoice_negative_total"
+	CodeInvoiceReference        = "invoice_reference_mismatch"
+	CodeAmountOverflow          = "amount_overflow"
+	CodePatchTestFailed         = "patch_test_failed"
+	CodeInvalidMFACode          = "invalid_mfa_code"
+	CodeInvalidMFAChallenge     = "invalid_mfa_challenge"
+	CodeMFAAlreadyEnabled       = "mfa_already_enabled"
+	CodeMFANotEnrolled          = "mfa_not_enrolled"
+	CodeMFANotEnabled           = "mfa_not_enabled"
+	CodeMFARequiredByRole       = "mfa_required_by_role"
+	CodeInvalidToken            = "invalid_token"
+	CodeRefreshTokenReused      = "refresh_token_reused"
+	CodeSessionRevoked          = "session_revoked"
+)
+
+// Problem is an RFC 7807 problem details document. Code is a stable, machine-readable
+// identifier clients can switch on instead of parsing Detail.
+type Problem struct {
+	Type   string      `json:"type"`
+	Title  string      `json:"title"`
+	Status int         `json:"status"`
+	Detail string      `json:"detail,omitempty"`
+	Code   string      `json:"code"`
+	Errors interface{} `json:"errors,omitempty"`
+}
+
+// NewProblem converts an error response into a problem document.
+func NewProblem(statusCode int, rep Response) Problem {
+	code := rep.Code
+	if code == "" {
+		code = StatusCode(statusCode)
+	}
+
+	return Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(statusCode),
+		Status: statusCode,
+		Detail: rep.Message,
+		Code:   code,
+		Errors: rep.Data,
+	}
+}
+
+// StatusCode derives the default problem code from the HTTP status, e.g. 404 becomes
+// "not_found".
+func StatusCode(statusCode int) string {
+	text := strings.ToLower(http.StatusText(statusCode))
+	if text == "" {
+		return "error"
+	}
+
+	text = strings.NewReplacer("'", "", "-", " ").Replace(text)
+	return strings.Join(strings.Fields(text), "_")
+}
+
+func problemJSON(w http.ResponseWriter, statusCode int, rep Response) {
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(NewProblem(statusCode, rep)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
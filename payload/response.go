@@ -15,6 +15,9 @@ type Response struct {
 	MessageType string      `json:"message_type"`
 	Message     string      `json:"message"`
 	Data        interface{} `json:"data"`
+	Meta        interface{} `json:"meta,omitempty"`
+	// Code overrides the problem code derived from the status of an error response.
+	Code string `json:"-"`
 }
 
 func NewResponse(messageType, message string, data interface{}) Response {
@@ -25,7 +28,45 @@ func NewResponse(messageType, message string, data interface{}) Response {
 	}
 }
 
+// NewPageResponse builds a response for one page of a list, with its pagination metadata.
+func NewPageResponse(messageType, message string, data, meta interface{}) Response {
+	return Response{
+		MessageType: messageType,
+		Message:     message,
+		Data:        data,
+		Meta:        meta,
+	}
+}
+
+// NewErrorResponse builds an error response whose problem code is code instead of the
+// one derived from the status.
+func NewErrorResponse(code, message string) Response {
+	return Response{
+		MessageType: MessageTypeError,
+		Message:     message,
+		Code:        code,
+	}
+}
+
+// NewValidationResponse builds the error response for input that failed validation.
+// fieldErrors becomes the errors member of the problem document.
+func NewValidationResponse(fieldErrors interface{}) Response {
+	return Response{
+		MessageType: MessageTypeError,
+		Message:     "Validation failed",
+		Data:        fieldErrors,
+		Code:        CodeValidationFailed,
+	}
+}
+
+// ResponseJSON writes rep with the given status. Error responses are rendered as RFC 7807
+// problem details instead of the success envelope.
 func ResponseJSON(w http.ResponseWriter, statusCode int, rep Response) {
+	if rep.MessageType == MessageTypeError {
+		problemJSON(w, statusCode, rep)
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	err := json.NewEncoder(w).Encode(&rep)
@@ -1,6 +1,8 @@
 package route
 
 import (
+	"net/http"
+
 	"github.com/IsraelTeo/api-paw-go/auth"
 	"github.com/IsraelTeo/api-paw-go/handler"
 	"github.com/IsraelTeo/api-paw-go/middelware"
@@ -8,12 +10,35 @@ import (
 )
 
 const (
+	jwksPath = "/.well-known/jwks.json"
+
 	registerPath = "/sign-up"
 	loginPath    = "/login"
+	refreshPath  = "/refresh"
+	logoutPath   = "/logout"
+	forgotPath   = "/forgot-password"
+	resetPath    = "/reset-password"
+	verifyPath   = "/verify-email"
+	resendPath   = "/resend-verification"
+
+	mfaLoginPath         = "/mfa"
+	mfaEnrollPath        = "/mfa/enroll"
+	mfaEnrollConfirmPath = "/mfa/enroll/confirm"
+
+	userBasicPath  = "/user"
+	userIDPath     = "/user/{id}"
+	usersPath      = "/users"
+	userRolesPath  = "/user/{id}/roles"
+	rolesPath      = "/roles"
+	userLinksPath  = "/user/{id}/links"
+	userUnlockPath = "/user/{id}/unlock"
+	mePath         = "/me"
+	mePasswordPath = "/me/password"
+	roleMFAPath    = "/role/{id}/mfa"
 
-	userBasicPath = "/user"
-	userIDPath    = "/user/{id}"
-	usersPath     = "/users"
+	meMFAEnrollPath  = "/me/mfa/enroll"
+	meMFAConfirmPath = "/me/mfa/confirm"
+	meMFADisablePath = "/me/mfa/disable"
 
 	employeTypeBasicPath = "/type"
 	employeTypeIDPath    = "/type/{id}"
@@ -26,50 +51,214 @@ const (
 	customerBasicPath = "/customer"
 	customerIDPath    = "/customer/{id}"
 	customersPath     = "/customers"
+	customerPetsPath  = "/customer/{id}/pets"
+	customerPetIDPath = "/customer/{id}/pets/{petId}"
 
 	petBasicPath = "/pet"
 	petIDPath    = "/pet/{id}"
 	petsPath     = "/pets"
+
+	appointmentBasicPath     = "/appointment"
+	appointmentIDPath        = "/appointment/{id}"
+	appointmentStatusPath    = "/appointment/{id}/status"
+	appointmentsPath         = "/appointments"
+	employeeAppointmentsPath = "/employee/{id}/appointments"
+
+	employeeShiftsPath       = "/employee/{id}/shifts"
+	employeeOverridesPath    = "/employee/{id}/overrides"
+	employeeAvailabilityPath = "/employee/{id}/availability"
+	shiftIDPath              = "/shift/{id}"
+	overrideIDPath           = "/override/{id}"
+
+	petRecordsPath        = "/pet/{id}/records"
+	petRecordIDPath       = "/pet/{id}/records/{recordId}"
+	petRecordVersionsPath = "/pet/{id}/records/{recordId}/versions"
+
+	vaccineBasicPath    = "/vaccine"
+	vaccineIDPath       = "/vaccine/{id}"
+	vaccinesPath        = "/vaccines"
+	petVaccinationsPath = "/pet/{id}/vaccinations"
+	dueVaccinationsPath = "/vaccinations/due"
+
+	invoiceBasicPath    = "/invoice"
+	invoiceIDPath       = "/invoice/{id}"
+	invoiceIssuePath    = "/invoice/{id}/issue"
+	invoiceVoidPath     = "/invoice/{id}/void"
+	invoicePaymentsPath = "/invoice/{id}/payments"
+	customerBalancePath = "/customer/{id}/balance"
+
+	serviceBasicPath = "/service"
+	serviceIDPath    = "/service/{id}"
+	servicesPath     = "/services"
+
+	productBasicPath     = "/product"
+	productIDPath        = "/product/{id}"
+	productsPath         = "/products"
+	productBatchesPath   = "/product/{id}/batches"
+	productStockOutPath  = "/product/{id}/stock-out"
+	productDispensePath  = "/product/{id}/dispense"
+	productMovementsPath = "/product/{id}/movements"
+	stockPath            = "/stock"
+	stockReconcilePath   = "/stock/reconcile"
+	expiringBatchesPath  = "/batches/expiring"
+
+	prescriptionBasicPath    = "/prescription"
+	prescriptionIDPath       = "/prescription/{id}"
+	prescriptionFillPath     = "/prescription/{id}/fill"
+	prescriptionDocumentPath = "/prescription/{id}/document"
+	petPrescriptionsPath     = "/pet/{id}/prescriptions"
+
+	petMeasurementsPath = "/pet/{id}/measurements"
+
+	searchPath = "/search"
+
+	portalPetsPath              = "/portal/pets"
+	portalAppointmentsPath      = "/portal/appointments"
+	portalAppointmentCancelPath = "/portal/appointments/{id}/cancel"
+	portalVaccinationsPath      = "/portal/vaccinations"
+	portalInvoicesPath          = "/portal/invoices"
 )
 
 func Init() *mux.Router {
 	routes := mux.NewRouter()
+	routes.NotFoundHandler = http.HandlerFunc(handler.NotFound)
+	routes.MethodNotAllowedHandler = http.HandlerFunc(handler.MethodNotAllowed)
+
+	routes.HandleFunc(jwksPath, middelware.Log(auth.JWKS)).Methods("GET")
 
 	apiAuth := routes.PathPrefix("/auth").Subrouter()
 
 	apiAuth.HandleFunc(registerPath, middelware.Log(handler.RegisterUser)).Methods("POST")
 	apiAuth.HandleFunc(loginPath, middelware.Log(auth.Login)).Methods("POST")
+	apiAuth.HandleFunc(refreshPath, middelware.Log(auth.Refresh)).Methods("POST")
+	apiAuth.HandleFunc(logoutPath, middelware.Log(auth.Logout)).Methods("POST")
+	apiAuth.HandleFunc(forgotPath, middelware.Log(auth.ForgotPassword)).Methods("POST")
+	apiAuth.HandleFunc(resetPath, middelware.Log(auth.ResetPassword)).Methods("POST")
+	apiAuth.HandleFunc(verifyPath, middelware.Log(auth.VerifyEmail)).Methods("POST")
+	apiAuth.HandleFunc(resendPath, middelware.Log(auth.ResendVerification)).Methods("POST")
+	apiAuth.HandleFunc(mfaLoginPath, middelware.Log(auth.LoginMFA)).Methods("POST")
+	apiAuth.HandleFunc(mfaEnrollPath, middelware.Log(auth.EnrollMFAChallenge)).Methods("POST")
+	apiAuth.HandleFunc(mfaEnrollConfirmPath, middelware.Log(auth.ConfirmMFAChallenge)).Methods("POST")
 
 	api := routes.PathPrefix("/api/v1").Subrouter()
 
-	api.HandleFunc(userIDPath, middelware.ValidateJWTAdmin(middelware.Log(handler.GetUserById))).Methods("GET")
-	api.HandleFunc(usersPath, middelware.ValidateJWTAdmin(middelware.Log(handler.GetAllUsers))).Methods("GET")
-	api.HandleFunc(userIDPath, middelware.Log(handler.UpdateUser)).Methods("PUT")
-	api.HandleFunc(userIDPath, middelware.Log(handler.DeleteUser)).Methods("DELETE")
-
-	api.HandleFunc(employeTypeBasicPath, middelware.ValidateJWTAdmin(middelware.Log(handler.SaveEmployeeType))).Methods("POST")
-	api.HandleFunc(employeTypeIDPath, middelware.ValidateJWTAdmin(middelware.Log(handler.GetEmployeeTypeById))).Methods("GET")
-	api.HandleFunc(employeTypesPath, middelware.ValidateJWTAdmin(middelware.Log(handler.GetAllEmployeeTypes))).Methods("GET")
-	api.HandleFunc(employeTypeIDPath, middelware.ValidateJWTAdmin(middelware.Log(handler.UpdateEmployeeType))).Methods("PUT")
-	api.HandleFunc(employeTypeIDPath, middelware.ValidateJWTAdmin(middelware.Log(handler.DeleteEmployeeType))).Methods("DELETE")
-
-	api.HandleFunc(employeeBasicPath, middelware.ValidateJWTAdmin(middelware.Log(handler.SaveEmployee))).Methods("POST")
-	api.HandleFunc(employeeIDPath, middelware.ValidateJWTAdmin(middelware.Log(handler.GetEmployeeById))).Methods("GET")
-	api.HandleFunc(employeesPath, middelware.ValidateJWTAdmin(middelware.Log(handler.GetAllEmployees))).Methods("GET")
-	api.HandleFunc(employeeIDPath, middelware.ValidateJWTAdmin(middelware.Log(handler.UpdateEmployee))).Methods("PUT")
-	api.HandleFunc(employeeIDPath, middelware.ValidateJWTAdmin(middelware.Log(handler.DeleteEmployee))).Methods("DELETE")
-
-	api.HandleFunc(customerBasicPath, middelware.ValidateJWT(middelware.Log(handler.SaveCustomer))).Methods("POST")
-	api.HandleFunc(customerIDPath, middelware.ValidateJWT(middelware.Log(handler.GetCustomerById))).Methods("GET")
-	api.HandleFunc(customersPath, middelware.ValidateJWT(middelware.Log(handler.GetAllCustomers))).Methods("GET")
-	api.HandleFunc(customerIDPath, middelware.ValidateJWT(middelware.Log(handler.UpdateCustomer))).Methods("PUT")
-	api.HandleFunc(customerIDPath, middelware.ValidateJWT(middelware.Log(handler.DeleteCustomer))).Methods("DELETE")
-
-	api.HandleFunc(petBasicPath, middelware.ValidateJWT(middelware.Log(handler.SavePet))).Methods("POST")
-	api.HandleFunc(petIDPath, middelware.ValidateJWT(middelware.Log(handler.GetPetById))).Methods("GET")
-	api.HandleFunc(petsPath, middelware.ValidateJWT(middelware.Log(handler.GetAllPets))).Methods("GET")
-	api.HandleFunc(petIDPath, middelware.ValidateJWT(middelware.Log(handler.UpdatePet))).Methods("PUT")
-	api.HandleFunc(petIDPath, middelware.ValidateJWT(middelware.Log(handler.DeletePet))).Methods("DELETE")
+	api.HandleFunc(userIDPath, middelware.RequirePermission("users:read", middelware.Log(handler.GetUserById))).Methods("GET")
+	api.HandleFunc(usersPath, middelware.RequirePermission("users:read", middelware.Log(handler.GetAllUsers))).Methods("GET")
+	api.HandleFunc(userIDPath, middelware.ValidateJWT(middelware.Log(handler.UpdateUser))).Methods("PUT", "PATCH")
+	api.HandleFunc(userIDPath, middelware.ValidateJWT(middelware.Log(handler.DeleteUser))).Methods("DELETE")
+	api.HandleFunc(userRolesPath, middelware.RequirePermission("users:write", middelware.Log(handler.UpdateUserRoles))).Methods("PUT")
+	api.HandleFunc(userUnlockPath, middelware.RequirePermission("users:write", middelware.Log(handler.UnlockUser))).Methods("POST")
+	api.HandleFunc(userLinksPath, middelware.RequirePermission("users:write", middelware.Log(handler.UpdateUserLinks))).Methods("PUT")
+	api.HandleFunc(mePath, middelware.ValidateJWT(middelware.Log(handler.GetMe))).Methods("GET")
+	api.HandleFunc(mePasswordPath, middelware.ValidateJWT(middelware.Log(handler.ChangePassword))).Methods("POST")
+	api.HandleFunc(meMFAEnrollPath, middelware.ValidateJWT(middelware.Log(auth.EnrollMFA))).Methods("POST")
+	api.HandleFunc(meMFAConfirmPath, middelware.ValidateJWT(middelware.Log(auth.ConfirmMFA))).Methods("POST")
+	api.HandleFunc(meMFADisablePath, middelware.ValidateJWT(middelware.Log(auth.DisableMFA))).Methods("POST")
+	api.HandleFunc(roleMFAPath, middelware.RequirePermission("roles:write", middelware.Log(handler.UpdateRoleMFA))).Methods("PUT")
+	api.HandleFunc(rolesPath, middelware.RequirePermission("roles:read", middelware.Log(handler.GetAllRoles))).Methods("GET")
+
+	api.HandleFunc(employeTypeBasicPath, middelware.RequirePermission("types:write", middelware.Log(handler.SaveEmployeeType))).Methods("POST")
+	api.HandleFunc(employeTypeIDPath, middelware.RequirePermission("types:read", middelware.Log(handler.GetEmployeeTypeById))).Methods("GET")
+	api.HandleFunc(employeTypesPath, middelware.RequirePermission("types:read", middelware.Log(handler.GetAllEmployeeTypes))).Methods("GET")
+	api.HandleFunc(employeTypeIDPath, middelware.RequirePermission("types:write", middelware.Log(handler.UpdateEmployeeType))).Methods("PUT", "PATCH")
+	api.HandleFunc(employeTypeIDPath, middelware.RequirePermission("types:write", middelware.Log(handler.DeleteEmployeeType))).Methods("DELETE")
+
+	api.HandleFunc(serviceBasicPath, middelware.RequirePermission("services:write", middelware.Log(handler.SaveService))).Methods("POST")
+	api.HandleFunc(serviceIDPath, middelware.RequirePermission("services:read", middelware.Log(handler.GetServiceById))).Methods("GET")
+	api.HandleFunc(servicesPath, middelware.RequirePermission("services:read", middelware.Log(handler.GetAllServices))).Methods("GET")
+	api.HandleFunc(serviceIDPath, middelware.RequirePermission("services:write", middelware.Log(handler.UpdateService))).Methods("PUT")
+	api.HandleFunc(serviceIDPath, middelware.RequirePermission("services:write", middelware.Log(handler.DeleteService))).Methods("DELETE")
+
+	api.HandleFunc(employeeBasicPath, middelware.RequirePermission("employees:write", middelware.Log(handler.SaveEmployee))).Methods("POST")
+	api.HandleFunc(employeeIDPath, middelware.RequirePermission("employees:read", middelware.Log(handler.GetEmployeeById))).Methods("GET")
+	api.HandleFunc(employeesPath, middelware.RequirePermission("employees:read", middelware.Log(handler.GetAllEmployees))).Methods("GET")
+	api.HandleFunc(employeeIDPath, middelware.RequirePermission("employees:write", middelware.Log(handler.UpdateEmployee))).Methods("PUT", "PATCH")
+	api.HandleFunc(employeeIDPath, middelware.RequirePermission("employees:write", middelware.Log(handler.DeleteEmployee))).Methods("DELETE")
+
+	api.HandleFunc(employeeShiftsPath, middelware.RequirePermission("shifts:write", middelware.Log(handler.SaveEmployeeShift))).Methods("POST")
+	api.HandleFunc(employeeShiftsPath, middelware.RequirePermission("shifts:read", middelware.Log(handler.GetEmployeeShifts))).Methods("GET")
+	api.HandleFunc(shiftIDPath, middelware.RequirePermission("shifts:write", middelware.Log(handler.DeleteShift))).Methods("DELETE")
+	api.HandleFunc(employeeOverridesPath, middelware.RequirePermission("shifts:write", middelware.Log(handler.SaveEmployeeOverride))).Methods("POST")
+	api.HandleFunc(employeeOverridesPath, middelware.RequirePermission("shifts:read", middelware.Log(handler.GetEmployeeOverrides))).Methods("GET")
+	api.HandleFunc(overrideIDPath, middelware.RequirePermission("shifts:write", middelware.Log(handler.DeleteOverride))).Methods("DELETE")
+	api.HandleFunc(employeeAvailabilityPath, middelware.RequirePermission("shifts:read", middelware.Log(handler.GetEmployeeAvailability))).Methods("GET")
+
+	api.HandleFunc(customerBasicPath, middelware.RequirePermission("customers:write", middelware.Log(handler.SaveCustomer))).Methods("POST")
+	api.HandleFunc(customerIDPath, middelware.RequirePermission("customers:read", middelware.Log(handler.GetCustomerById))).Methods("GET")
+	api.HandleFunc(customersPath, middelware.RequirePermission("customers:read", middelware.Log(handler.GetAllCustomers))).Methods("GET")
+	api.HandleFunc(customerIDPath, middelware.RequirePermission("customers:write", middelware.Log(handler.UpdateCustomer))).Methods("PUT", "PATCH")
+	api.HandleFunc(customerIDPath, middelware.RequirePermission("customers:write", middelware.Log(handler.DeleteCustomer))).Methods("DELETE")
+	api.HandleFunc(customerPetsPath, middelware.RequirePermission("customers:read", middelware.Log(handler.GetCustomerPets))).Methods("GET")
+	api.HandleFunc(customerPetsPath, middelware.RequirePermission("customers:write", middelware.Log(handler.AttachCustomerPet))).Methods("POST")
+	api.HandleFunc(customerPetIDPath, middelware.RequirePermission("customers:write", middelware.Log(handler.DetachCustomerPet))).Methods("DELETE")
+
+	api.HandleFunc(petBasicPath, middelware.RequirePermission("pets:write", middelware.Log(handler.SavePet))).Methods("POST")
+	api.HandleFunc(petIDPath, middelware.RequirePermission("pets:read", middelware.Log(handler.GetPetById))).Methods("GET")
+	api.HandleFunc(petsPath, middelware.RequirePermission("pets:read", middelware.Log(handler.GetAllPets))).Methods("GET")
+	api.HandleFunc(petIDPath, middelware.RequirePermission("pets:write", middelware.Log(handler.UpdatePet))).Methods("PUT", "PATCH")
+	api.HandleFunc(petIDPath, middelware.RequirePermission("pets:write", middelware.Log(handler.DeletePet))).Methods("DELETE")
+
+	api.HandleFunc(appointmentBasicPath, middelware.RequirePermission("appointments:write", middelware.Log(handler.SaveAppointment))).Methods("POST")
+	api.HandleFunc(appointmentIDPath, middelware.RequirePermission("appointments:read", middelware.Log(handler.GetAppointmentById))).Methods("GET")
+	api.HandleFunc(appointmentsPath, middelware.RequirePermission("appointments:read", middelware.Log(handler.GetAllAppointments))).Methods("GET")
+	api.HandleFunc(employeeAppointmentsPath, middelware.RequirePermission("appointments:read", middelware.Log(handler.GetEmployeeAppointments))).Methods("GET")
+	api.HandleFunc(appointmentIDPath, middelware.RequirePermission("appointments:write", middelware.Log(handler.UpdateAppointment))).Methods("PUT")
+	api.HandleFunc(appointmentStatusPath, middelware.RequirePermission("appointments:write", middelware.Log(handler.UpdateAppointmentStatus))).Methods("PUT")
+	api.HandleFunc(appointmentIDPath, middelware.RequirePermission("appointments:delete", middelware.Log(handler.DeleteAppointment))).Methods("DELETE")
+
+	api.HandleFunc(petRecordsPath, middelware.RequirePermission("records:write", middelware.Log(handler.SaveClinicalRecord))).Methods("POST")
+	api.HandleFunc(petRecordsPath, middelware.RequirePermission("records:read", middelware.Log(handler.GetClinicalRecords))).Methods("GET")
+	api.HandleFunc(petRecordIDPath, middelware.RequirePermission("records:read", middelware.Log(handler.GetClinicalRecordById))).Methods("GET")
+	api.HandleFunc(petRecordVersionsPath, middelware.RequirePermission("records:write", middelware.Log(handler.SaveClinicalRecordVersion))).Methods("POST")
+
+	api.HandleFunc(vaccineBasicPath, middelware.RequirePermission("vaccines:write", middelware.Log(handler.SaveVaccine))).Methods("POST")
+	api.HandleFunc(vaccineIDPath, middelware.RequirePermission("vaccines:read", middelware.Log(handler.GetVaccineById))).Methods("GET")
+	api.HandleFunc(vaccinesPath, middelware.RequirePermission("vaccines:read", middelware.Log(handler.GetAllVaccines))).Methods("GET")
+	api.HandleFunc(vaccineIDPath, middelware.RequirePermission("vaccines:write", middelware.Log(handler.UpdateVaccine))).Methods("PUT")
+	api.HandleFunc(vaccineIDPath, middelware.RequirePermission("vaccines:write", middelware.Log(handler.DeleteVaccine))).Methods("DELETE")
+
+	api.HandleFunc(petVaccinationsPath, middelware.RequirePermission("vaccinations:write", middelware.Log(handler.SaveVaccination))).Methods("POST")
+	api.HandleFunc(petVaccinationsPath, middelware.RequirePermission("vaccinations:read", middelware.Log(handler.GetPetVaccinations))).Methods("GET")
+	api.HandleFunc(dueVaccinationsPath, middelware.RequirePermission("vaccinations:read", middelware.Log(handler.GetDueVaccinations))).Methods("GET")
+
+	api.HandleFunc(invoiceBasicPath, middelware.RequirePermission("invoices:write", middelware.Log(handler.SaveInvoice))).Methods("POST")
+	api.HandleFunc(invoiceIDPath, middelware.RequirePermission("invoices:read", middelware.Log(handler.GetInvoiceById))).Methods("GET")
+	api.HandleFunc(invoiceIDPath, middelware.RequirePermission("invoices:write", middelware.Log(handler.UpdateInvoice))).Methods("PUT")
+	api.HandleFunc(invoiceIssuePath, middelware.RequirePermission("invoices:write", middelware.Log(handler.IssueInvoice))).Methods("POST")
+	api.HandleFunc(invoiceVoidPath, middelware.RequirePermission("invoices:void", middelware.Log(handler.VoidInvoice))).Methods("POST")
+	api.HandleFunc(invoicePaymentsPath, middelware.RequirePermission("payments:write", middelware.Log(handler.SaveInvoicePayment))).Methods("POST")
+	api.HandleFunc(customerBalancePath, middelware.RequirePermission("invoices:read", middelware.Log(handler.GetCustomerBalance))).Methods("GET")
+
+	api.HandleFunc(productBasicPath, middelware.RequirePermission("products:write", middelware.Log(handler.SaveProduct))).Methods("POST")
+	api.HandleFunc(productIDPath, middelware.RequirePermission("products:read", middelware.Log(handler.GetProductById))).Methods("GET")
+	api.HandleFunc(productsPath, middelware.RequirePermission("products:read", middelware.Log(handler.GetAllProducts))).Methods("GET")
+	api.HandleFunc(productIDPath, middelware.RequirePermission("products:write", middelware.Log(handler.UpdateProduct))).Methods("PUT")
+	api.HandleFunc(productIDPath, middelware.RequirePermission("products:write", middelware.Log(handler.DeleteProduct))).Methods("DELETE")
+	api.HandleFunc(productBatchesPath, middelware.RequirePermission("stock:write", middelware.Log(handler.SaveBatch))).Methods("POST")
+	api.HandleFunc(productStockOutPath, middelware.RequirePermission("stock:write", middelware.Log(handler.RemoveStock))).Methods("POST")
+	api.HandleFunc(productDispensePath, middelware.RequirePermission("stock:write", middelware.Log(handler.DispenseStock))).Methods("POST")
+	api.HandleFunc(productMovementsPath, middelware.RequirePermission("stock:read", middelware.Log(handler.GetProductMovements))).Methods("GET")
+	api.HandleFunc(stockPath, middelware.RequirePermission("stock:read", middelware.Log(handler.GetStock))).Methods("GET")
+	api.HandleFunc(stockReconcilePath, middelware.RequirePermission("stock:reconcile", middelware.Log(handler.ReconcileStock))).Methods("GET")
+	api.HandleFunc(expiringBatchesPath, middelware.RequirePermission("stock:read", middelware.Log(handler.GetExpiringBatches))).Methods("GET")
+
+	api.HandleFunc(prescriptionBasicPath, middelware.RequirePermission("prescriptions:write", middelware.Log(handler.SavePrescription))).Methods("POST")
+	api.HandleFunc(prescriptionIDPath, middelware.RequirePermission("prescriptions:read", middelware.Log(handler.GetPrescriptionById))).Methods("GET")
+	api.HandleFunc(prescriptionFillPath, middelware.RequirePermission("prescriptions:fill", middelware.Log(handler.FillPrescription))).Methods("POST")
+	api.HandleFunc(prescriptionDocumentPath, middelware.RequirePermission("prescriptions:read", middelware.Log(handler.GetPrescriptionDocument))).Methods("GET")
+	api.HandleFunc(petPrescriptionsPath, middelware.RequirePermission("prescriptions:read", middelware.Log(handler.GetPetPrescriptions))).Methods("GET")
+
+	api.HandleFunc(petMeasurementsPath, middelware.RequirePermission("pets:write", middelware.Log(handler.SavePetMeasurement))).Methods("POST")
+	api.HandleFunc(petMeasurementsPath, middelware.RequirePermission("pets:read", middelware.Log(handler.GetPetMeasurements))).Methods("GET")
+
+	api.HandleFunc(searchPath, middelware.RequirePermission("search:read", middelware.Log(handler.Search))).Methods("GET")
+
+	api.HandleFunc(portalPetsPath, middelware.RequirePermission("portal:access", middelware.Log(handler.GetPortalPets))).Methods("GET")
+	api.HandleFunc(portalAppointmentsPath, middelware.RequirePermission("portal:access", middelware.Log(handler.GetPortalAppointments))).Methods("GET")
+	api.HandleFunc(portalAppointmentsPath, middelware.RequirePermission("portal:access", middelware.Log(handler.RequestPortalAppointment))).Methods("POST")
+	api.HandleFunc(portalAppointmentCancelPath, middelware.RequirePermission("portal:access", middelware.Log(handler.CancelPortalAppointment))).Methods("POST")
+	api.HandleFunc(portalVaccinationsPath, middelware.RequirePermission("portal:access", middelware.Log(handler.GetPortalVaccinations))).Methods("GET")
+	api.HandleFunc(portalInvoicesPath, middelware.RequirePermission("portal:access", middelware.Log(handler.GetPortalInvoices))).Methods("GET")
 
 	return routes
 }
@@ -0,0 +1,132 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Document is what MemoryIndex stores for one entity.
+type Document struct {
+	Type     string
+	ID       uint
+	Title    string
+	Subtitle string
+	Text     string
+}
+
+type docKey struct {
+	Type string
+	ID   uint
+}
+
+// MemoryIndex is an in-process inverted index, meant for tests and small data sets.
+// Query terms match tokens by prefix; exact token matches rank higher.
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[docKey]Document
+	postings map[string]map[docKey]bool
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{docs: make(map[docKey]Document), postings: make(map[string]map[docKey]bool)}
+}
+
+func (m *MemoryIndex) Add(doc Document) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := docKey{doc.Type, doc.ID}
+	m.remove(key)
+	m.docs[key] = doc
+	for _, token := range Tokenize(doc.Title + " " + doc.Subtitle + " " + doc.Text) {
+		if m.postings[token] == nil {
+			m.postings[token] = make(map[docKey]bool)
+		}
+		m.postings[token][key] = true
+	}
+}
+
+func (m *MemoryIndex) Remove(docType string, id uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(docKey{docType, id})
+}
+
+func (m *MemoryIndex) remove(key docKey) {
+	if _, ok := m.docs[key]; !ok {
+		return
+	}
+	delete(m.docs, key)
+	for token, keys := range m.postings {
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(m.postings, token)
+		}
+	}
+}
+
+// Search requires every query term to match some token of the document.
+func (m *MemoryIndex) Search(_ context.Context, query string, limit int) (Results, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := emptyResults()
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return results, nil
+	}
+
+	var scores map[docKey]float64
+	for _, term := range terms {
+		termScores := make(map[docKey]float64)
+		for token, keys := range m.postings {
+			if !strings.HasPrefix(token, term) {
+				continue
+			}
+			weight := 1.0
+			if token == term {
+				weight = 2
+			}
+			for key := range keys {
+				if weight > termScores[key] {
+					termScores[key] = weight
+				}
+			}
+		}
+
+		if scores == nil {
+			scores = termScores
+			continue
+		}
+		for key, score := range scores {
+			if extra, ok := termScores[key]; ok {
+				scores[key] = score + extra
+			} else {
+				delete(scores, key)
+			}
+		}
+	}
+
+	ranked := make([]Result, 0, len(scores))
+	for key, score := range scores {
+		doc := m.docs[key]
+		ranked = append(ranked, Result{Type: doc.Type, ID: doc.ID, Title: doc.Title, Subtitle: doc.Subtitle, Score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+
+	counts := make(map[string]int)
+	for _, result := range ranked {
+		if counts[result.Type] < limit {
+			counts[result.Type]++
+			results.add(result)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,142 @@
+package search
+
+import (
+	"context"
+	"testing"
+)
+
+func resultIDs(results []Result) []uint {
+	ids := make([]uint, len(results))
+	for i, result := range results {
+		ids[i] = result.ID
+	}
+	return ids
+}
+
+func sameIDs(got, want []uint) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMemoryIndexRanksExactAbovePrefix(t *testing.T) {
+	index := NewMemoryIndex()
+	index.Add(Document{Type: TypePet, ID: 1, Title: "Maxwell", Subtitle: "dog"})
+	index.Add(Document{Type: TypePet, ID: 2, Title: "Max", Subtitle: "cat"})
+	index.Add(Document{Type: TypePet, ID: 3, Title: "Maximus", Subtitle: "dog"})
+
+	results, err := index.Search(context.Background(), "max", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := resultIDs(results.Pets), []uint{2, 1, 3}; !sameIDs(got, want) {
+		t.Fatalf("pets = %v, want %v", got, want)
+	}
+	if results.Pets[0].Score <= results.Pets[1].Score {
+		t.Errorf("exact match score %v is not above prefix score %v", results.Pets[0].Score, results.Pets[1].Score)
+	}
+}
+
+func TestMemoryIndexRequiresEveryTerm(t *testing.T) {
+	index := NewMemoryIndex()
+	index.Add(Document{Type: TypeCustomer, ID: 1, Title: "Ana Torres", Text: "ana@example.com"})
+	index.Add(Document{Type: TypeCustomer, ID: 2, Title: "Ana Ruiz", Text: "ruiz@example.com"})
+	index.Add(Document{Type: TypeCustomer, ID: 3, Title: "Luis Torres", Text: "luis@example.com"})
+
+	tests := []struct {
+		query string
+		want  []uint
+	}{
+		{query: "ana", want: []uint{1, 2}},
+		{query: "ana torres", want: []uint{1}},
+		{query: "TORRES, Ana", want: []uint{1}},
+		{query: "an tor", want: []uint{1}},
+		{query: "ana luis", want: []uint{}},
+		{query: "  ", want: []uint{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := index.Search(context.Background(), tt.query, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := resultIDs(results.Customers); !sameIDs(got, tt.want) {
+				t.Errorf("customers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryIndexLimitsEachType(t *testing.T) {
+	index := NewMemoryIndex()
+	for id := uint(1); id <= 4; id++ {
+		index.Add(Document{Type: TypeCustomer, ID: id, Title: "Garcia"})
+		index.Add(Document{Type: TypePet, ID: id, Title: "Garcia"})
+	}
+	index.Add(Document{Type: TypeEmployee, ID: 1, Title: "Garcia"})
+
+	results, err := index.Search(context.Background(), "garcia", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := resultIDs(results.Customers), []uint{1, 2}; !sameIDs(got, want) {
+		t.Errorf("customers = %v, want %v", got, want)
+	}
+	if got, want := resultIDs(results.Pets), []uint{1, 2}; !sameIDs(got, want) {
+		t.Errorf("pets = %v, want %v", got, want)
+	}
+	if got, want := resultIDs(results.Employees), []uint{1}; !sameIDs(got, want) {
+		t.Errorf("employees = %v, want %v", got, want)
+	}
+}
+
+func TestMemoryIndexReindexAndRemove(t *testing.T) {
+	index := NewMemoryIndex()
+	index.Add(Document{Type: TypePet, ID: 1, Title: "Rocky"})
+	index.Add(Document{Type: TypePet, ID: 1, Title: "Bruno"})
+
+	results, _ := index.Search(context.Background(), "rocky", 10)
+	if len(results.Pets) != 0 {
+		t.Errorf("old title still matches after re-adding: %v", resultIDs(results.Pets))
+	}
+
+	index.Remove(TypePet, 1)
+	results, _ = index.Search(context.Background(), "bruno", 10)
+	if len(results.Pets) != 0 {
+		t.Errorf("removed document still matches: %v", resultIDs(results.Pets))
+	}
+}
+
+func TestBooleanQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "ana", want: "+ana*"},
+		{query: "Ana Torres", want: "+ana* +torres*"},
+		{query: "-ana +torres", want: "+ana* +torres*"},
+		{query: `"ana torres"`, want: "+ana* +torres*"},
+		{query: "ana* ~luis >max <rex", want: "+ana* +luis* +max* +rex*"},
+		{query: "(ana OR luis)", want: "+ana* +or* +luis*"},
+		{query: "ana@example.com", want: "+ana* +example* +com*"},
+		{query: "+-*~()<>@\"", want: ""},
+		{query: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := booleanQuery(tt.query); got != tt.want {
+				t.Errorf("booleanQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
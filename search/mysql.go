@@ -0,0 +1,90 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// MySQLIndex searches the live tables through their FULLTEXT indexes, so it needs no
+// separate indexing step. The indexes are created by db.MigrateDataBase.
+type MySQLIndex struct {
+	db *gorm.DB
+}
+
+func NewMySQLIndex(db *gorm.DB) *MySQLIndex {
+	return &MySQLIndex{db: db}
+}
+
+// searchSource describes how one table is searched and shown.
+type searchSource struct {
+	Type     string
+	Table    string
+	Columns  string
+	Title    string
+	Subtitle string
+}
+
+var searchSources = []searchSource{
+	{
+		Type:     TypeCustomer,
+		Table:    "customers",
+		Columns:  "first_name, last_name, dni, email, phone_number",
+		Title:    "CONCAT(first_name, ' ', last_name)",
+		Subtitle: "CONCAT(dni, ' · ', email, ' · ', COALESCE(phone_number, ''))",
+	},
+	{
+		Type:     TypePet,
+		Table:    "pets",
+		Columns:  "name, race",
+		Title:    "name",
+		Subtitle: "CONCAT(specie, ' · ', race)",
+	},
+	{
+		Type:     TypeEmployee,
+		Table:    "employees",
+		Columns:  "first_name, last_name",
+		Title:    "CONCAT(first_name, ' ', last_name)",
+		Subtitle: "email",
+	},
+}
+
+func (m *MySQLIndex) Search(ctx context.Context, query string, limit int) (Results, error) {
+	results := emptyResults()
+	against := booleanQuery(query)
+	if against == "" {
+		return results, nil
+	}
+
+	for _, source := range searchSources {
+		match := "MATCH(" + source.Columns + ") AGAINST(? IN BOOLEAN MODE)"
+		var rows []Result
+		err := m.db.WithContext(ctx).Table(source.Table).
+			Select("? AS type, id, "+source.Title+" AS title, "+source.Subtitle+" AS subtitle, "+match+" AS score", source.Type, against).
+			Where(match, against).
+			Where("deleted_at IS NULL").
+			Order("score DESC").Order("id").
+			Limit(limit).
+			Scan(&rows).Error
+		if err != nil {
+			return results, err
+		}
+
+		for _, row := range rows {
+			results.add(row)
+		}
+	}
+
+	return results, nil
+}
+
+// booleanQuery turns free text into a boolean-mode query where every term is required
+// and matches as a prefix. Tokenizing drops the operators users could otherwise inject.
+func booleanQuery(query string) string {
+	terms := Tokenize(query)
+	for i, term := range terms {
+		terms[i] = "+" + term + "*"
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,67 @@
+// Package search finds customers, pets and employees from a single free-text query.
+package search
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+const (
+	TypeCustomer = "customer"
+	TypePet      = "pet"
+	TypeEmployee = "employee"
+)
+
+// Result is one match, ranked by Score within its type.
+type Result struct {
+	Type     string  `json:"type"`
+	ID       uint    `json:"id"`
+	Title    string  `json:"title"`
+	Subtitle string  `json:"subtitle,omitempty"`
+	Score    float64 `json:"score"`
+}
+
+// Results groups matches by entity type, best match first.
+type Results struct {
+	Customers []Result `json:"customers"`
+	Pets      []Result `json:"pets"`
+	Employees []Result `json:"employees"`
+}
+
+// SearchIndex answers a query with at most limit results per type.
+type SearchIndex interface {
+	Search(ctx context.Context, query string, limit int) (Results, error)
+}
+
+var defaultIndex SearchIndex = NewMemoryIndex()
+
+func SetDefault(index SearchIndex) {
+	defaultIndex = index
+}
+
+func Default() SearchIndex {
+	return defaultIndex
+}
+
+// Tokenize lowercases text and splits it on anything that is not a letter or digit.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func (r *Results) add(result Result) {
+	switch result.Type {
+	case TypeCustomer:
+		r.Customers = append(r.Customers, result)
+	case TypePet:
+		r.Pets = append(r.Pets, result)
+	case TypeEmployee:
+		r.Employees = append(r.Employees, result)
+	}
+}
+
+func emptyResults() Results {
+	return Results{Customers: []Result{}, Pets: []Result{}, Employees: []Result{}}
+}
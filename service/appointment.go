@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEmployeeDoubleBooked = errors.New("employee already has an appointment in this time slot")
+	ErrPetDoubleBooked      = errors.New("pet already has an appointment in this time slot")
+	ErrPetNotOwned          = errors.New("pet does not belong to customer")
+	ErrEmployeeNotQualified = errors.New("employee type cannot perform this service")
+	ErrInvalidTimeSlot      = errors.New("appointment must end after it starts")
+)
+
+// appointmentTransitions lists the statuses an appointment can move to from each status.
+var appointmentTransitions = map[string][]string{
+	model.AppointmentStatusScheduled: {model.AppointmentStatusCheckedIn, model.AppointmentStatusCancelled, model.AppointmentStatusNoShow},
+	model.AppointmentStatusCheckedIn: {model.AppointmentStatusCompleted, model.AppointmentStatusCancelled},
+}
+
+func IsValidAppointmentStatus(status string) bool {
+	switch status {
+	case model.AppointmentStatusScheduled,
+		model.AppointmentStatusCheckedIn,
+		model.AppointmentStatusCompleted,
+		model.AppointmentStatusCancelled,
+		model.AppointmentStatusNoShow:
+		return true
+	}
+	return false
+}
+
+func CanTransitionAppointment(from, to string) bool {
+	for _, next := range appointmentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// BookAppointment creates or reschedules an appointment. The employee and pet rows are
+// locked for the duration of the transaction so two concurrent bookings cannot both pass
+// the overlap check.
+func BookAppointment(tx *gorm.DB, appointment *model.Appointment) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		employee := model.Employee{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&employee, appointment.EmployeeID).Error; err != nil {
+			return err
+		}
+
+		if appointment.ServiceID != nil {
+			item := model.Service{}
+			if err := tx.First(&item, *appointment.ServiceID).Error; err != nil {
+				return err
+			}
+			if item.TypeID != employee.TypeID {
+				return ErrEmployeeNotQualified
+			}
+			if appointment.EndAt.IsZero() {
+				appointment.EndAt = appointment.StartAt.Add(time.Duration(item.DurationMinutes) * time.Minute)
+			}
+		}
+
+		if !appointment.EndAt.After(appointment.StartAt) {
+			return ErrInvalidTimeSlot
+		}
+
+		if works, err := EmployeeWorksDuring(tx, appointment.EmployeeID, appointment.StartAt, appointment.EndAt); err != nil {
+			return err
+		} else if !works {
+			return ErrEmployeeUnavailable
+		}
+
+		pet := model.Pet{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&pet, appointment.PetID).Error; err != nil {
+			return err
+		}
+
+		owned, err := CustomerOwnsPet(tx, appointment.CustomerID, appointment.PetID)
+		if err != nil {
+			return err
+		}
+		if !owned {
+			return ErrPetNotOwned
+		}
+
+		if busy, err := hasOverlappingAppointment(tx, "employee_id", appointment.EmployeeID, appointment); err != nil {
+			return err
+		} else if busy {
+			return ErrEmployeeDoubleBooked
+		}
+
+		if busy, err := hasOverlappingAppointment(tx, "pet_id", appointment.PetID, appointment); err != nil {
+			return err
+		} else if busy {
+			return ErrPetDoubleBooked
+		}
+
+		if appointment.ID == 0 {
+			return tx.Omit(clause.Associations).Create(appointment).Error
+		}
+		return tx.Omit(clause.Associations).Save(appointment).Error
+	})
+}
+
+func hasOverlappingAppointment(tx *gorm.DB, field string, value uint, appointment *model.Appointment) (bool, error) {
+	query := tx.Model(&model.Appointment{}).
+		Where(field+" = ?", value).
+		Where("status NOT IN ?", []string{model.AppointmentStatusCancelled, model.AppointmentStatusNoShow}).
+		Where("start_at < ? AND end_at > ?", appointment.EndAt, appointment.StartAt)
+	if appointment.ID != 0 {
+		query = query.Where("id <> ?", appointment.ID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// AppointmentPeriod returns the [start, end) range of the day or ISO week containing date.
+func AppointmentPeriod(date time.Time, period string) (time.Time, time.Time, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	switch period {
+	case "", "day":
+		return start, start.AddDate(0, 0, 1), nil
+	case "week":
+		offset := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7), nil
+	}
+	return time.Time{}, time.Time{}, errors.New("period must be day or week")
+}
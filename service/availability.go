@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmployeeUnavailable = errors.New("employee is not working during this time slot")
+	ErrInvalidShift        = errors.New("shift must end after it starts")
+)
+
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func ValidateShiftTemplate(shift *model.ShiftTemplate) error {
+	start, err := time.Parse("15:04", shift.StartTime)
+	if err != nil {
+		return err
+	}
+	end, err := time.Parse("15:04", shift.EndTime)
+	if err != nil {
+		return err
+	}
+	if !end.After(start) {
+		return ErrInvalidShift
+	}
+	return nil
+}
+
+// EmployeeWorkingSlots expands the weekly shift templates of an employee over
+// [from, to), adds extra shifts and removes leave and other unavailable overrides.
+// Employees without any shift template have no schedule yet and count as working the
+// whole range, so availability and booking keep working for existing staff while their
+// shifts are entered.
+func EmployeeWorkingSlots(tx *gorm.DB, employeeID uint, from, to time.Time) ([]TimeSlot, error) {
+	var templates []model.ShiftTemplate
+	if err := tx.Where("employee_id = ?", employeeID).Find(&templates).Error; err != nil {
+		return nil, err
+	}
+
+	var overrides []model.ShiftOverride
+	err := tx.Where("employee_id = ? AND start_at < ? AND end_at > ?", employeeID, to, from).
+		Find(&overrides).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return workingSlots(templates, overrides, from, to), nil
+}
+
+func workingSlots(templates []model.ShiftTemplate, overrides []model.ShiftOverride, from, to time.Time) []TimeSlot {
+	if len(templates) == 0 {
+		return clipSlots(subtractSlots([]TimeSlot{{Start: from, End: to}}, blockedSlots(overrides)), from, to)
+	}
+
+	var working []TimeSlot
+	local := from.In(time.Local)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.Local)
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, template := range templates {
+			if time.Weekday(template.Weekday) != day.Weekday() {
+				continue
+			}
+			working = append(working, TimeSlot{
+				Start: atClock(day, template.StartTime),
+				End:   atClock(day, template.EndTime),
+			})
+		}
+	}
+
+	for _, override := range overrides {
+		if override.Kind == model.ShiftOverrideExtraShift {
+			working = append(working, TimeSlot{Start: override.StartAt, End: override.EndAt})
+		}
+	}
+
+	return clipSlots(subtractSlots(mergeSlots(working), blockedSlots(overrides)), from, to)
+}
+
+// blockedSlots returns the overrides that take time away: leave and other absences.
+func blockedSlots(overrides []model.ShiftOverride) []TimeSlot {
+	var blocked []TimeSlot
+	for _, override := range overrides {
+		if override.Kind != model.ShiftOverrideExtraShift {
+			blocked = append(blocked, TimeSlot{Start: override.StartAt, End: override.EndAt})
+		}
+	}
+	return blocked
+}
+
+// EmployeeFreeSlots returns the working time of an employee in [from, to) that is not
+// taken by an active appointment.
+func EmployeeFreeSlots(tx *gorm.DB, employeeID uint, from, to time.Time) ([]TimeSlot, error) {
+	working, err := EmployeeWorkingSlots(tx, employeeID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var appointments []model.Appointment
+	err = tx.Where("employee_id = ?", employeeID).
+		Where("status NOT IN ?", []string{model.AppointmentStatusCancelled, model.AppointmentStatusNoShow}).
+		Where("start_at < ? AND end_at > ?", to, from).
+		Find(&appointments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make([]TimeSlot, 0, len(appointments))
+	for _, appointment := range appointments {
+		busy = append(busy, TimeSlot{Start: appointment.StartAt, End: appointment.EndAt})
+	}
+	return subtractSlots(working, busy), nil
+}
+
+// EmployeeWorksDuring reports whether [start, end) falls entirely inside the employee's
+// working time, as computed by EmployeeWorkingSlots.
+func EmployeeWorksDuring(tx *gorm.DB, employeeID uint, start, end time.Time) (bool, error) {
+	working, err := EmployeeWorkingSlots(tx, employeeID, start, end)
+	if err != nil {
+		return false, err
+	}
+
+	for _, slot := range working {
+		if !slot.Start.After(start) && !slot.End.Before(end) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SplitSlots cuts free time into consecutive slots of the given length, dropping any
+// remainder shorter than length.
+func SplitSlots(slots []TimeSlot, length time.Duration) []TimeSlot {
+	var split []TimeSlot
+	for _, slot := range slots {
+		for start := slot.Start; !start.Add(length).After(slot.End); start = start.Add(length) {
+			split = append(split, TimeSlot{Start: start, End: start.Add(length)})
+		}
+	}
+	return split
+}
+
+func atClock(day time.Time, clock string) time.Time {
+	parsed, _ := time.Parse("15:04", clock)
+	return time.Date(day.Year(), day.Month(), day.Day(), parsed.Hour(), parsed.Minute(), 0, 0, day.Location())
+}
+
+func mergeSlots(slots []TimeSlot) []TimeSlot {
+	if len(slots) == 0 {
+		return nil
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Start.Before(slots[j].Start) })
+	merged := []TimeSlot{slots[0]}
+	for _, slot := range slots[1:] {
+		last := &merged[len(merged)-1]
+		if !slot.Start.After(last.End) {
+			if slot.End.After(last.End) {
+				last.End = slot.End
+			}
+			continue
+		}
+		merged = append(merged, slot)
+	}
+	return merged
+}
+
+func subtractSlots(slots, remove []TimeSlot) []TimeSlot {
+	result := slots
+	for _, cut := range remove {
+		var next []TimeSlot
+		for _, slot := range result {
+			if !cut.Start.Before(slot.End) || !cut.End.After(slot.Start) {
+				next = append(next, slot)
+				continue
+			}
+			if cut.Start.After(slot.Start) {
+				next = append(next, TimeSlot{Start: slot.Start, End: cut.Start})
+			}
+			if cut.End.Before(slot.End) {
+				next = append(next, TimeSlot{Start: cut.End, End: slot.End})
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+func clipSlots(slots []TimeSlot, from, to time.Time) []TimeSlot {
+	var clipped []TimeSlot
+	for _, slot := range slots {
+		if slot.Start.Before(from) {
+			slot.Start = from
+		}
+		if slot.End.After(to) {
+			slot.End = to
+		}
+		if slot.End.After(slot.Start) {
+			clipped = append(clipped, slot)
+		}
+	}
+	return clipped
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+)
+
+// Monday, 4 March 2024.
+var availabilityDay = time.Date(2024, 3, 4, 0, 0, 0, 0, time.Local)
+
+func at(hour, minute int) time.Time {
+	return availabilityDay.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func slot(startHour, endHour int) TimeSlot {
+	return TimeSlot{Start: at(startHour, 0), End: at(endHour, 0)}
+}
+
+func sameSlots(got, want []TimeSlot) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSubtractSlots(t *testing.T) {
+	tests := []struct {
+		name   string
+		slots  []TimeSlot
+		remove []TimeSlot
+		want   []TimeSlot
+	}{
+		{name: "nothing to remove", slots: []TimeSlot{slot(9, 17)}, want: []TimeSlot{slot(9, 17)}},
+		{name: "hole in the middle", slots: []TimeSlot{slot(9, 17)}, remove: []TimeSlot{slot(12, 13)}, want: []TimeSlot{slot(9, 12), slot(13, 17)}},
+		{name: "cut at the start", slots: []TimeSlot{slot(9, 17)}, remove: []TimeSlot{slot(8, 10)}, want: []TimeSlot{slot(10, 17)}},
+		{name: "cut at the end", slots: []TimeSlot{slot(9, 17)}, remove: []TimeSlot{slot(16, 18)}, want: []TimeSlot{slot(9, 16)}},
+		{name: "whole slot removed", slots: []TimeSlot{slot(9, 17)}, remove: []TimeSlot{slot(9, 17)}, want: nil},
+		{name: "touching cut keeps the slot", slots: []TimeSlot{slot(9, 12)}, remove: []TimeSlot{slot(12, 13)}, want: []TimeSlot{slot(9, 12)}},
+		{
+			name:   "several cuts across slots",
+			slots:  []TimeSlot{slot(8, 12), slot(14, 18)},
+			remove: []TimeSlot{slot(9, 10), slot(11, 15), slot(17, 19)},
+			want:   []TimeSlot{slot(8, 9), slot(10, 11), slot(15, 17)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := subtractSlots(tt.slots, tt.remove); !sameSlots(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeSlots(t *testing.T) {
+	got := mergeSlots([]TimeSlot{slot(14, 16), slot(9, 12), slot(11, 13), slot(16, 18)})
+	if want := []TimeSlot{slot(9, 13), slot(14, 18)}; !sameSlots(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSplitSlots(t *testing.T) {
+	tests := []struct {
+		name   string
+		slots  []TimeSlot
+		length time.Duration
+		want   []TimeSlot
+	}{
+		{name: "exact fit", slots: []TimeSlot{slot(9, 11)}, length: time.Hour, want: []TimeSlot{slot(9, 10), slot(10, 11)}},
+		{
+			name: "remainder dropped", slots: []TimeSlot{{Start: at(9, 0), End: at(10, 40)}}, length: 30 * time.Minute,
+			want: []TimeSlot{{Start: at(9, 0), End: at(9, 30)}, {Start: at(9, 30), End: at(10, 0)}, {Start: at(10, 0), End: at(10, 30)}},
+		},
+		{name: "slot shorter than length", slots: []TimeSlot{{Start: at(9, 0), End: at(9, 20)}}, length: 30 * time.Minute, want: nil},
+		{name: "each slot split on its own", slots: []TimeSlot{slot(9, 10), slot(14, 15)}, length: time.Hour, want: []TimeSlot{slot(9, 10), slot(14, 15)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitSlots(tt.slots, tt.length); !sameSlots(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkingSlots(t *testing.T) {
+	monday := []model.ShiftTemplate{{Weekday: int(time.Monday), StartTime: "09:00", EndTime: "17:00"}}
+	override := func(kind string, startHour, endHour int) model.ShiftOverride {
+		return model.ShiftOverride{Kind: kind, StartAt: at(startHour, 0), EndAt: at(endHour, 0)}
+	}
+
+	tests := []struct {
+		name      string
+		templates []model.ShiftTemplate
+		overrides []model.ShiftOverride
+		from, to  time.Time
+		want      []TimeSlot
+	}{
+		{name: "template", templates: monday, from: at(0, 0), to: at(24, 0), want: []TimeSlot{slot(9, 17)}},
+		{name: "clipped to the range", templates: monday, from: at(12, 0), to: at(20, 0), want: []TimeSlot{slot(12, 17)}},
+		{
+			name: "template on another weekday", from: at(0, 0), to: at(24, 0), want: nil,
+			templates: []model.ShiftTemplate{{Weekday: int(time.Tuesday), StartTime: "09:00", EndTime: "17:00"}},
+		},
+		{
+			name: "leave and extra shift", templates: monday, from: at(0, 0), to: at(24, 0),
+			overrides: []model.ShiftOverride{override(model.ShiftOverrideVacation, 12, 14), override(model.ShiftOverrideExtraShift, 17, 19)},
+			want:      []TimeSlot{slot(9, 12), slot(14, 19)},
+		},
+		{name: "no templates means the whole range", from: at(8, 0), to: at(18, 0), want: []TimeSlot{slot(8, 18)}},
+		{
+			name: "no templates still honours absences", from: at(8, 0), to: at(18, 0),
+			overrides: []model.ShiftOverride{override(model.ShiftOverrideSickLeave, 10, 12), override(model.ShiftOverrideExtraShift, 20, 22)},
+			want:      []TimeSlot{slot(8, 10), slot(12, 18)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workingSlots(tt.templates, tt.overrides, tt.from, tt.to); !sameSlots(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func CreateClinicalRecord(tx *gorm.DB, record *model.ClinicalRecord, version *model.ClinicalRecordVersion) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(record).Error; err != nil {
+			return err
+		}
+
+		version.ID = 0
+		version.RecordID = record.ID
+		version.Version = 1
+		version.CorrectionReason = ""
+		if err := tx.Omit(clause.Associations).Create(version).Error; err != nil {
+			return err
+		}
+
+		record.Current = version
+		if !hasVitals(version) {
+			return nil
+		}
+
+		return RecordMeasurement(tx, &model.PetMeasurement{
+			PetID:       record.PetID,
+			MeasuredAt:  version.CreatedAt,
+			Weight:      version.Weight,
+			Temperature: version.Temperature,
+			HeartRate:   version.HeartRate,
+			EmployeeID:  &version.EmployeeID,
+		})
+	})
+}
+
+func hasVitals(version *model.ClinicalRecordVersion) bool {
+	return version.Weight != nil || version.Temperature != nil || version.HeartRate != nil
+}
+
+// AppendClinicalRecordVersion stores a correction as the next version of record. The
+// record row is locked so concurrent corrections cannot claim the same version number.
+func AppendClinicalRecordVersion(tx *gorm.DB, record *model.ClinicalRecord, version *model.ClinicalRecordVersion) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.ClinicalRecord{}, record.ID).Error; err != nil {
+			return err
+		}
+
+		var latest uint
+		if err := tx.Model(&model.ClinicalRecordVersion{}).
+			Where("record_id = ?", record.ID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		version.ID = 0
+		version.RecordID = record.ID
+		version.Version = latest + 1
+		if err := tx.Omit(clause.Associations).Create(version).Error; err != nil {
+			return err
+		}
+
+		record.Current = version
+		return nil
+	})
+}
+
+// SetCurrentClinicalVersion points Current at the highest version of each record.
+// Versions are expected to be loaded in ascending order.
+func SetCurrentClinicalVersion(records []model.ClinicalRecord) {
+	for i := range records {
+		if n := len(records[i].Versions); n > 0 {
+			records[i].Current = &records[i].Versions[n-1]
+		}
+	}
+}
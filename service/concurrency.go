@@ -0,0 +1,38 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrStaleEntity is returned when a conditional write finds that the row changed after
+// it was read.
+var ErrStaleEntity = errors.New("entity was modified by another request")
+
+// SaveIfUnmodified saves entity only while its row still has the updated_at it was read
+// with, so two editors starting from the same version cannot overwrite each other.
+func SaveIfUnmodified(tx *gorm.DB, entity interface{}, updatedAt time.Time) error {
+	// Selecting every column keeps Save from falling back to an upsert when no row matches.
+	result := tx.Select("*").Where("updated_at = ?", updatedAt).Save(entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleEntity
+	}
+	return nil
+}
+
+// DeleteIfUnmodified deletes entity under the same condition as SaveIfUnmodified.
+func DeleteIfUnmodified(tx *gorm.DB, entity interface{}, updatedAt time.Time) error {
+	result := tx.Where("updated_at = ?", updatedAt).Delete(entity)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleEntity
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const customerPetsTable = "customer_pets"
+
+func CustomerOwnsPet(tx *gorm.DB, customerID, petID uint) (bool, error) {
+	var count int64
+	err := tx.Table(customerPetsTable).
+		Where("customer_id = ? AND pet_id = ?", customerID, petID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// LockCustomer loads a customer with its pets, locking the customer row and the pet rows
+// until tx ends. Attaching or detaching a pet updates the customer row, so it waits too.
+func LockCustomer(tx *gorm.DB, id uint) (model.Customer, error) {
+	customer := model.Customer{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Pets", func(db *gorm.DB) *gorm.DB {
+			return db.Clauses(clause.Locking{Strength: "UPDATE"})
+		}).
+		First(&customer, id).Error
+	return customer, err
+}
+
+// AttachPets links every pet in petIDs to customer. It returns gorm.ErrRecordNotFound
+// when any of the pets does not exist.
+func AttachPets(tx *gorm.DB, customer *model.Customer, petIDs []uint) error {
+	if len(petIDs) == 0 {
+		return nil
+	}
+
+	var pets []model.Pet
+	if err := tx.Where("id IN ?", petIDs).Find(&pets).Error; err != nil {
+		return err
+	}
+
+	unique := make(map[uint]struct{}, len(petIDs))
+	for _, id := range petIDs {
+		unique[id] = struct{}{}
+	}
+	if len(pets) != len(unique) {
+		return gorm.ErrRecordNotFound
+	}
+
+	return tx.Model(customer).Association("Pets").Append(&pets)
+}
+
+func DetachPetFromOwners(tx *gorm.DB, petID uint) error {
+	return tx.Table(customerPetsTable).Where("pet_id = ?", petID).Delete(nil).Error
+}
+
+func PetOwnerContacts(tx *gorm.DB, petID uint) ([]OwnerContact, error) {
+	var owners []OwnerContact
+	err := tx.Model(&model.Customer{}).
+		Select("customers.id, customers.first_name, customers.last_name, customers.email, customers.phone_number").
+		Joins("JOIN "+customerPetsTable+" ON "+customerPetsTable+".customer_id = customers.id").
+		Where(customerPetsTable+".pet_id = ?", petID).
+		Scan(&owners).Error
+	return owners, err
+}
+
+// PetsOwnerContacts loads the owners of every pet in petIDs with a single query, keyed
+// by pet id.
+func PetsOwnerContacts(tx *gorm.DB, petIDs []uint) (map[uint][]OwnerContact, error) {
+	owners := make(map[uint][]OwnerContact, len(petIDs))
+	if len(petIDs) == 0 {
+		return owners, nil
+	}
+
+	var rows []struct {
+		OwnerContact
+		PetID uint
+	}
+	err := tx.Model(&model.Customer{}).
+		Select("customers.id, customers.first_name, customers.last_name, customers.email, customers.phone_number, "+customerPetsTable+".pet_id").
+		Joins("JOIN "+customerPetsTable+" ON "+customerPetsTable+".customer_id = customers.id").
+		Where(customerPetsTable+".pet_id IN ?", petIDs).
+		Order("customers.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		owners[row.PetID] = append(owners[row.PetID], row.OwnerContact)
+	}
+	return owners, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInsufficientStock = errors.New("not enough unexpired stock for this product")
+
+type ProductStock struct {
+	model.Product
+	Available int64 `json:"available"`
+	Expired   int64 `json:"expired"`
+	LowStock  bool  `json:"low_stock"`
+}
+
+type StockDiscrepancy struct {
+	BatchID   uint  `json:"batch_id"`
+	ProductID uint  `json:"product_id"`
+	Recorded  int64 `json:"recorded"`
+	Ledger    int64 `json:"ledger"`
+}
+
+type StockRemoval struct {
+	ProductID  uint
+	Quantity   int64
+	Kind       string
+	PetID      *uint
+	EmployeeID *uint
+	Reason     string
+}
+
+func ReceiveStock(tx *gorm.DB, batch *model.Batch, employeeID *uint) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+
+		movement := model.StockMovement{
+			ProductID:  batch.ProductID,
+			BatchID:    batch.ID,
+			Kind:       model.StockMovementIn,
+			Quantity:   batch.Quantity,
+			EmployeeID: employeeID,
+			Reason:     "lot " + batch.LotNumber,
+		}
+		return tx.Create(&movement).Error
+	})
+}
+
+// RemoveStock takes stock out of the unexpired batches of a product, earliest expiry
+// first, and records one ledger movement per batch touched.
+func RemoveStock(tx *gorm.DB, removal StockRemoval) ([]model.StockMovement, error) {
+	var movements []model.StockMovement
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		var batches []model.Batch
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND quantity > 0 AND expires_at > ?", removal.ProductID, time.Now()).
+			Order("expires_at").
+			Find(&batches).Error
+		if err != nil {
+			return err
+		}
+
+		remaining := removal.Quantity
+		for _, batch := range batches {
+			if remaining == 0 {
+				break
+			}
+
+			taken := min(batch.Quantity, remaining)
+			remaining -= taken
+			if err := tx.Model(&batch).Update("quantity", gorm.Expr("quantity - ?", taken)).Error; err != nil {
+				return err
+			}
+
+			movements = append(movements, model.StockMovement{
+				ProductID:  removal.ProductID,
+				BatchID:    batch.ID,
+				Kind:       removal.Kind,
+				Quantity:   -taken,
+				PetID:      removal.PetID,
+				EmployeeID: removal.EmployeeID,
+				Reason:     removal.Reason,
+			})
+		}
+
+		if remaining > 0 {
+			return ErrInsufficientStock
+		}
+		return tx.Create(&movements).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movements, nil
+}
+
+func ProductStocks(tx *gorm.DB) ([]ProductStock, error) {
+	var products []model.Product
+	if err := tx.Order("name").Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	type batchTotal struct {
+		ProductID uint
+		Available int64
+		Expired   int64
+	}
+	var totals []batchTotal
+	now := time.Now()
+	err := tx.Model(&model.Batch{}).
+		Select("product_id, "+
+			"COALESCE(SUM(CASE WHEN expires_at > ? THEN quantity ELSE 0 END), 0) AS available, "+
+			"COALESCE(SUM(CASE WHEN expires_at <= ? THEN quantity ELSE 0 END), 0) AS expired", now, now).
+		Group("product_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[uint]batchTotal, len(totals))
+	for _, total := range totals {
+		byProduct[total.ProductID] = total
+	}
+
+	stocks := make([]ProductStock, 0, len(products))
+	for _, product := range products {
+		total := byProduct[product.ID]
+		stocks = append(stocks, ProductStock{
+			Product:   product,
+			Available: total.Available,
+			Expired:   total.Expired,
+			LowStock:  total.Available <= product.LowStockThreshold,
+		})
+	}
+	return stocks, nil
+}
+
+func ExpiringBatches(tx *gorm.DB, until time.Time) ([]model.Batch, error) {
+	var batches []model.Batch
+	err := tx.Where("quantity > 0 AND expires_at <= ?", until).
+		Order("expires_at").
+		Find(&batches).Error
+	return batches, err
+}
+
+// ReconcileStock rebuilds every batch quantity from the movement ledger and returns the
+// batches whose stored quantity does not match.
+func ReconcileStock(tx *gorm.DB) ([]StockDiscrepancy, error) {
+	var discrepancies []StockDiscrepancy
+	err := tx.Model(&model.Batch{}).
+		Select("batches.id AS batch_id, batches.product_id, batches.quantity AS recorded, COALESCE(SUM(stock_movements.quantity), 0) AS ledger").
+		Joins("LEFT JOIN stock_movements ON stock_movements.batch_id = batches.id").
+		Group("batches.id, batches.product_id, batches.quantity").
+		Having("recorded <> ledger").
+		Scan(&discrepancies).Error
+	return discrepancies, err
+}
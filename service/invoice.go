@@ -0,0 +1,294 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvoiceNotDraft      = errors.New("only draft invoices can be changed")
+	ErrInvoiceNotPayable    = errors.New("only issued invoices accept payments")
+	ErrInvoiceNotVoidable   = errors.New("only draft or issued invoices can be voided")
+	ErrInvoiceHasPayments   = errors.New("invoices with payments cannot be voided")
+	ErrInvoiceNegativeTotal = errors.New("discounts exceed the invoice subtotal")
+	ErrPaymentExceedsTotal  = errors.New("payment exceeds the outstanding balance")
+	ErrInvoiceReference     = errors.New("appointment or pet does not belong to the invoice customer")
+)
+
+// CalculateInvoice fills in the line totals and the invoice subtotal, discount, tax and
+// total. Tax is applied to the discounted subtotal and rounded half up to the cent. It
+// returns model.ErrMoneyOverflow when an amount does not fit in a Money.
+func CalculateInvoice(invoice *model.Invoice) error {
+	var subtotal, discounts model.Money
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		gross, err := line.UnitPrice.Mul(int64(line.Quantity))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if line.Discount > gross {
+			return fmt.Errorf("line %d: %w", i+1, ErrInvoiceNegativeTotal)
+		}
+
+		line.Total = gross - line.Discount
+		if subtotal, err = subtotal.Add(gross); err != nil {
+			return err
+		}
+		if discounts, err = discounts.Add(line.Discount); err != nil {
+			return err
+		}
+	}
+
+	discounts, err := discounts.Add(invoice.Discount)
+	if err != nil {
+		return err
+	}
+	taxable := subtotal - discounts
+	if taxable < 0 {
+		return ErrInvoiceNegativeTotal
+	}
+
+	tax, err := taxable.Mul(int64(invoice.TaxRateBps))
+	if err != nil {
+		return err
+	}
+	if tax, err = tax.Add(5000); err != nil {
+		return err
+	}
+
+	invoice.Subtotal = subtotal
+	invoice.DiscountTotal = discounts
+	invoice.TaxTotal = tax / 10000
+	if invoice.Total, err = taxable.Add(invoice.TaxTotal); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateInvoiceReferences checks that the customer exists and that the optional
+// appointment and pets on the invoice belong to that customer.
+func ValidateInvoiceReferences(tx *gorm.DB, invoice *model.Invoice) error {
+	if err := tx.First(&model.Customer{}, invoice.CustomerID).Error; err != nil {
+		return err
+	}
+
+	if invoice.AppointmentID != nil {
+		var count int64
+		err := tx.Model(&model.Appointment{}).
+			Where("id = ? AND customer_id = ?", *invoice.AppointmentID, invoice.CustomerID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrInvoiceReference
+		}
+	}
+
+	petIDs := []*uint{invoice.PetID}
+	for _, line := range invoice.Lines {
+		petIDs = append(petIDs, line.PetID)
+	}
+	for _, petID := range petIDs {
+		if petID == nil {
+			continue
+		}
+		owned, err := CustomerOwnsPet(tx, invoice.CustomerID, *petID)
+		if err != nil {
+			return err
+		}
+		if !owned {
+			return ErrInvoiceReference
+		}
+	}
+
+	return nil
+}
+
+// SaveDraftInvoice creates a draft invoice or replaces the lines and amounts of an
+// existing one. An existing invoice is locked and must still be a draft, so an issue or
+// void committed since it was read is not overwritten, and only the columns a draft may
+// change are written.
+func SaveDraftInvoice(tx *gorm.DB, invoice *model.Invoice) error {
+	if invoice.Status != model.InvoiceStatusDraft {
+		return ErrInvoiceNotDraft
+	}
+
+	if err := applyCatalogPrices(tx, invoice); err != nil {
+		return err
+	}
+
+	if err := CalculateInvoice(invoice); err != nil {
+		return err
+	}
+
+	for i := range invoice.Lines {
+		invoice.Lines[i].ID = 0
+	}
+
+	return tx.Transaction(func(tx *gorm.DB) error {
+		if invoice.ID == 0 {
+			return tx.Omit("Customer", "Payments").Create(invoice).Error
+		}
+
+		current := model.Invoice{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, invoice.ID).Error; err != nil {
+			return err
+		}
+
+		if current.Status != model.InvoiceStatusDraft {
+			return ErrInvoiceNotDraft
+		}
+
+		if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&model.InvoiceLine{}).Error; err != nil {
+			return err
+		}
+
+		for i := range invoice.Lines {
+			invoice.Lines[i].InvoiceID = invoice.ID
+		}
+		if len(invoice.Lines) > 0 {
+			if err := tx.Create(&invoice.Lines).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(invoice).Omit(clause.Associations).
+			Select("CustomerID", "AppointmentID", "PetID", "TaxRateBps", "Discount",
+				"Subtotal", "DiscountTotal", "TaxTotal", "Total", "UpdatedAt").
+			Updates(invoice).Error
+	})
+}
+
+// applyCatalogPrices prices every line that references a catalog service with the
+// current catalog price, so the front desk cannot bill a different amount by hand.
+func applyCatalogPrices(tx *gorm.DB, invoice *model.Invoice) error {
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		if line.ServiceID == nil {
+			continue
+		}
+
+		item := model.Service{}
+		if err := tx.First(&item, *line.ServiceID).Error; err != nil {
+			return err
+		}
+
+		line.UnitPrice = item.Price
+		if line.Description == "" {
+			line.Description = item.Name
+		}
+	}
+	return nil
+}
+
+// IssueInvoice numbers a draft invoice and issues it. An invoice whose total is zero is
+// settled on issue, since it could never take a payment. Like RecordPayment it locks the
+// row, so a concurrent void or issue sees the new status.
+func IssueInvoice(tx *gorm.DB, invoice *model.Invoice) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		current := model.Invoice{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, invoice.ID).Error; err != nil {
+			return err
+		}
+
+		if current.Status != model.InvoiceStatusDraft {
+			return ErrInvoiceNotDraft
+		}
+
+		now := time.Now()
+		number := fmt.Sprintf("INV-%06d", invoice.ID)
+		invoice.Number = &number
+		invoice.Status = model.InvoiceStatusIssued
+		invoice.IssuedAt = &now
+		columns := []interface{}{"Status", "IssuedAt"}
+		if current.Total == 0 {
+			invoice.Status = model.InvoiceStatusPaid
+			invoice.PaidAt = &now
+			columns = append(columns, "PaidAt")
+		}
+		return tx.Model(invoice).Select("Number", columns...).Updates(invoice).Error
+	})
+}
+
+// VoidInvoice voids a draft or unpaid issued invoice. The row is locked so a payment
+// being recorded at the same time cannot end up on a void invoice.
+func VoidInvoice(tx *gorm.DB, invoice *model.Invoice) error {
+	return tx.Transaction(func(tx *gorm.DB) error {
+		current := model.Invoice{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, invoice.ID).Error; err != nil {
+			return err
+		}
+
+		if current.Status != model.InvoiceStatusDraft && current.Status != model.InvoiceStatusIssued {
+			return ErrInvoiceNotVoidable
+		}
+		if current.AmountPaid > 0 {
+			return ErrInvoiceHasPayments
+		}
+
+		now := time.Now()
+		invoice.Status = model.InvoiceStatusVoid
+		invoice.VoidedAt = &now
+		return tx.Model(invoice).Select("Status", "VoidedAt").Updates(invoice).Error
+	})
+}
+
+// RecordPayment applies a partial or full payment to an issued invoice. The invoice row
+// is locked so concurrent payments cannot exceed the outstanding balance.
+func RecordPayment(tx *gorm.DB, invoiceID uint, payment *model.Payment) (model.Invoice, error) {
+	invoice := model.Invoice{}
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, invoiceID).Error; err != nil {
+			return err
+		}
+
+		if invoice.Status != model.InvoiceStatusIssued {
+			return ErrInvoiceNotPayable
+		}
+		if payment.Amount > invoice.Balance() {
+			return ErrPaymentExceedsTotal
+		}
+
+		payment.InvoiceID = invoice.ID
+		if payment.PaidAt.IsZero() {
+			payment.PaidAt = time.Now()
+		}
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+
+		invoice.AmountPaid += payment.Amount
+		columns := []interface{}{"AmountPaid"}
+		if invoice.Balance() == 0 {
+			invoice.Status = model.InvoiceStatusPaid
+			invoice.PaidAt = &payment.PaidAt
+			columns = append(columns, "Status", "PaidAt")
+		}
+		return tx.Model(&invoice).Select(columns[0], columns[1:]...).Updates(&invoice).Error
+	})
+
+	return invoice, err
+}
+
+func CustomerOutstandingInvoices(tx *gorm.DB, customerID uint) ([]model.Invoice, model.Money, error) {
+	var invoices []model.Invoice
+	err := tx.Preload("Lines").Preload("Payments").
+		Where("customer_id = ? AND status = ?", customerID, model.InvoiceStatusIssued).
+		Order("issued_at").
+		Find(&invoices).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var outstanding model.Money
+	for _, invoice := range invoices {
+		outstanding += invoice.Balance()
+	}
+	return invoices, outstanding, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+)
+
+func TestCalculateInvoice(t *testing.T) {
+	tests := []struct {
+		name     string
+		invoice  model.Invoice
+		subtotal model.Money
+		discount model.Money
+		tax      model.Money
+		total    model.Money
+		wantErr  error
+	}{
+		{
+			name: "single line without tax",
+			invoice: model.Invoice{Lines: []model.InvoiceLine{
+				{Quantity: 2, UnitPrice: 1250},
+			}},
+			subtotal: 2500, total: 2500,
+		},
+		{
+			name: "tax rounds half up",
+			invoice: model.Invoice{TaxRateBps: 1800, Lines: []model.InvoiceLine{
+				{Quantity: 1, UnitPrice: 25}, // 25 * 18% = 4.5 cents
+			}},
+			subtotal: 25, tax: 5, total: 30,
+		},
+		{
+			name: "tax rounds down below half",
+			invoice: model.Invoice{TaxRateBps: 1800, Lines: []model.InvoiceLine{
+				{Quantity: 1, UnitPrice: 24}, // 24 * 18% = 4.32 cents
+			}},
+			subtotal: 24, tax: 4, total: 28,
+		},
+		{
+			name: "tax applies after line and invoice discounts",
+			invoice: model.Invoice{TaxRateBps: 1000, Discount: 500, Lines: []model.InvoiceLine{
+				{Quantity: 3, UnitPrice: 1000, Discount: 500},
+				{Quantity: 1, UnitPrice: 2000},
+			}},
+			subtotal: 5000, discount: 1000, tax: 400, total: 4400,
+		},
+		{
+			name: "line discount above its gross amount",
+			invoice: model.Invoice{Lines: []model.InvoiceLine{
+				{Quantity: 1, UnitPrice: 100, Discount: 101},
+			}},
+			wantErr: ErrInvoiceNegativeTotal,
+		},
+		{
+			name: "invoice discount above the subtotal",
+			invoice: model.Invoice{Discount: 300, Lines: []model.InvoiceLine{
+				{Quantity: 2, UnitPrice: 100},
+			}},
+			wantErr: ErrInvoiceNegativeTotal,
+		},
+		{
+			name: "line amount overflows",
+			invoice: model.Invoice{Lines: []model.InvoiceLine{
+				{Quantity: 4, UnitPrice: math.MaxInt64 / 2},
+			}},
+			wantErr: model.ErrMoneyOverflow,
+		},
+		{
+			name: "tax overflows",
+			invoice: model.Invoice{TaxRateBps: 10000, Lines: []model.InvoiceLine{
+				{Quantity: 1, UnitPrice: math.MaxInt64 / 100},
+			}},
+			wantErr: model.ErrMoneyOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invoice := tt.invoice
+			err := CalculateInvoice(&invoice)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if invoice.Subtotal != tt.subtotal || invoice.DiscountTotal != tt.discount ||
+				invoice.TaxTotal != tt.tax || invoice.Total != tt.total {
+				t.Errorf("got subtotal %v, discount %v, tax %v, total %v; want %v, %v, %v, %v",
+					invoice.Subtotal, invoice.DiscountTotal, invoice.TaxTotal, invoice.Total,
+					tt.subtotal, tt.discount, tt.tax, tt.total)
+			}
+		})
+	}
+}
+
+func TestCalculateInvoiceLineTotals(t *testing.T) {
+	invoice := model.Invoice{Lines: []model.InvoiceLine{
+		{Quantity: 3, UnitPrice: 1000, Discount: 500},
+		{Quantity: 1, UnitPrice: 999},
+	}}
+	if err := CalculateInvoice(&invoice); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []model.Money{2500, 999}
+	for i, line := range invoice.Lines {
+		if line.Total != want[i] {
+			t.Errorf("line %d total = %v, want %v", i+1, line.Total, want[i])
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// reservedListParams are query parameters that are never treated as filters. "token"
+// carries the JWT for clients that cannot send headers.
+var reservedListParams = map[string]bool{"limit": true, "cursor": true, "sort": true, "token": true}
+
+var ErrInvalidListQuery = errors.New("invalid list query")
+
+// ListSpec whitelists, per resource, the query parameters that filter and sort a list.
+// Keys are the parameter names, values the columns they map to.
+type ListSpec struct {
+	Filters     map[string]string
+	Sorts       map[string]string
+	DefaultSort string
+}
+
+type ListQuery struct {
+	Limit   int
+	Filters map[string]string
+	Sort    string
+	Desc    bool
+	column  string
+	cursor  *listCursor
+}
+
+// PageMeta is the pagination metadata returned with every page.
+type PageMeta struct {
+	Limit      int    `json:"limit"`
+	Sort       string `json:"sort"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// listCursor points just past the last row of a page: its sort value and id, so rows
+// sharing a sort value are neither skipped nor repeated.
+type listCursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v"`
+	ID    uint        `json:"id"`
+}
+
+// ParseListQuery reads limit, cursor, sort and filters from values. Parameters that are
+// not whitelisted in spec are rejected rather than ignored.
+func ParseListQuery(values url.Values, spec ListSpec) (ListQuery, error) {
+	query := ListQuery{Limit: defaultListLimit, Filters: map[string]string{}}
+
+	for name, vals := range values {
+		if reservedListParams[name] {
+			continue
+		}
+		column, ok := spec.Filters[name]
+		if !ok {
+			return query, fmt.Errorf("%w: unknown filter %q", ErrInvalidListQuery, name)
+		}
+		query.Filters[column] = vals[0]
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxListLimit {
+			return query, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidListQuery, maxListLimit)
+		}
+		query.Limit = limit
+	}
+
+	sort := values.Get("sort")
+	if sort == "" {
+		sort = spec.DefaultSort
+	}
+	query.Sort = sort
+	if strings.HasPrefix(sort, "-") {
+		query.Desc = true
+		sort = sort[1:]
+	}
+	column, ok := spec.Sorts[sort]
+	if !ok {
+		return query, fmt.Errorf("%w: unknown sort %q", ErrInvalidListQuery, sort)
+	}
+	query.column = column
+
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, err := decodeListCursor(raw)
+		if err != nil || cursor.Sort != query.Sort {
+			return query, fmt.Errorf("%w: invalid cursor", ErrInvalidListQuery)
+		}
+		query.cursor = &cursor
+	}
+
+	return query, nil
+}
+
+// FindPage loads one page of T using query. Preloads and scopes set on tx are kept.
+func FindPage[T any](tx *gorm.DB, query ListQuery) ([]T, PageMeta, error) {
+	meta := PageMeta{Limit: query.Limit, Sort: query.Sort}
+
+	for column, value := range query.Filters {
+		tx = tx.Where(fmt.Sprintf("%s = ?", column), value)
+	}
+
+	op, direction := ">", "ASC"
+	if query.Desc {
+		op, direction = "<", "DESC"
+	}
+
+	if query.cursor != nil {
+		tx = tx.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND id %s ?))", query.column, op, query.column, op),
+			query.cursor.Value, query.cursor.Value, query.cursor.ID)
+	}
+
+	var items []T
+	err := tx.Order(query.column + " " + direction).Order("id " + direction).
+		Limit(query.Limit + 1).
+		Find(&items).Error
+	if err != nil {
+		return nil, meta, err
+	}
+
+	if len(items) <= query.Limit {
+		return items, meta, nil
+	}
+
+	items = items[:query.Limit]
+	meta.HasMore = true
+
+	cursor, err := cursorFor(tx, &items[len(items)-1], query)
+	if err != nil {
+		return nil, meta, err
+	}
+	meta.NextCursor = cursor
+	return items, meta, nil
+}
+
+var listSchemas sync.Map
+
+func cursorFor(tx *gorm.DB, item interface{}, query ListQuery) (string, error) {
+	s, err := schema.Parse(item, &listSchemas, tx.NamingStrategy)
+	if err != nil {
+		return "", err
+	}
+
+	sortField, idField := s.LookUpField(query.column), s.LookUpField("id")
+	if sortField == nil || idField == nil {
+		return "", fmt.Errorf("list column %q not found", query.column)
+	}
+
+	row := reflect.ValueOf(item).Elem()
+	value, _ := sortField.ValueOf(context.Background(), row)
+	id, _ := idField.ValueOf(context.Background(), row)
+
+	// Las fechas se guardan en el formato que MySQL compara con sus columnas DATETIME
+	if t, ok := value.(time.Time); ok {
+		value = t.Format("2006-01-02 15:04:05.000000")
+	}
+
+	data, err := json.Marshal(listCursor{Sort: query.Sort, Value: value, ID: id.(uint)})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeListCursor(raw string) (listCursor, error) {
+	var cursor listCursor
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
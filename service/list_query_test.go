@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+var testListSpec = ListSpec{
+	Filters:     map[string]string{"species": "species", "owner": "customer_id"},
+	Sorts:       map[string]string{"name": "name", "created": "created_at", "id": "id"},
+	DefaultSort: "id",
+}
+
+func TestParseListQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		limit   int
+		sort    string
+		desc    bool
+		column  string
+		filters map[string]string
+		wantErr bool
+	}{
+		{name: "defaults", query: "", limit: defaultListLimit, sort: "id", column: "id", filters: map[string]string{}},
+		{name: "explicit limit", query: "limit=5", limit: 5, sort: "id", column: "id", filters: map[string]string{}},
+		{name: "maximum limit", query: "limit=100", limit: maxListLimit, sort: "id", column: "id", filters: map[string]string{}},
+		{name: "zero limit", query: "limit=0", wantErr: true},
+		{name: "limit above maximum", query: "limit=101", wantErr: true},
+		{name: "non numeric limit", query: "limit=ten", wantErr: true},
+		{name: "ascending sort", query: "sort=name", limit: defaultListLimit, sort: "name", column: "name", filters: map[string]string{}},
+		{name: "descending sort", query: "sort=-created", limit: defaultListLimit, sort: "-created", desc: true, column: "created_at", filters: map[string]string{}},
+		{name: "unknown sort", query: "sort=password", wantErr: true},
+		{name: "unknown descending sort", query: "sort=-password", wantErr: true},
+		{
+			name: "filters map to columns", query: "species=dog&owner=7",
+			limit: defaultListLimit, sort: "id", column: "id",
+			filters: map[string]string{"species": "dog", "customer_id": "7"},
+		},
+		{name: "unknown filter", query: "role=admin", wantErr: true},
+		{name: "token is reserved", query: "token=abc", limit: defaultListLimit, sort: "id", column: "id", filters: map[string]string{}},
+		{name: "malformed cursor", query: "cursor=!!!", wantErr: true},
+		{name: "cursor that is not json", query: "cursor=bm90LWpzb24", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseListQuery(values, testListSpec)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidListQuery) {
+					t.Fatalf("error = %v, want ErrInvalidListQuery", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Limit != tt.limit || got.Sort != tt.sort || got.Desc != tt.desc || got.column != tt.column {
+				t.Errorf("got limit=%d sort=%q desc=%v column=%q, want limit=%d sort=%q desc=%v column=%q",
+					got.Limit, got.Sort, got.Desc, got.column, tt.limit, tt.sort, tt.desc, tt.column)
+			}
+			if len(got.Filters) != len(tt.filters) {
+				t.Fatalf("filters = %v, want %v", got.Filters, tt.filters)
+			}
+			for column, value := range tt.filters {
+				if got.Filters[column] != value {
+					t.Errorf("filter %s = %q, want %q", column, got.Filters[column], value)
+				}
+			}
+		})
+	}
+}
+
+type listQueryTestRow struct {
+	ID        uint
+	Name      string
+	CreatedAt time.Time
+}
+
+func TestListCursorRoundTrip(t *testing.T) {
+	tx := &gorm.DB{Config: &gorm.Config{NamingStrategy: schema.NamingStrategy{}}}
+	row := listQueryTestRow{ID: 42, Name: "Firulais", CreatedAt: time.Date(2024, 3, 9, 14, 5, 7, 123456000, time.UTC)}
+
+	tests := []struct {
+		sort  string
+		value interface{}
+	}{
+		{sort: "name", value: "Firulais"},
+		{sort: "-created", value: "2024-03-09 14:05:07.123456"},
+		{sort: "id", value: float64(42)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			query, err := ParseListQuery(url.Values{"sort": {tt.sort}}, testListSpec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := cursorFor(tx, &row, query)
+			if err != nil {
+				t.Fatalf("cursorFor: %v", err)
+			}
+
+			next, err := ParseListQuery(url.Values{"sort": {tt.sort}, "cursor": {raw}}, testListSpec)
+			if err != nil {
+				t.Fatalf("cursor was rejected: %v", err)
+			}
+			if next.cursor == nil {
+				t.Fatal("cursor was not decoded")
+			}
+			if next.cursor.ID != row.ID || next.cursor.Value != tt.value || next.cursor.Sort != tt.sort {
+				t.Errorf("cursor = %+v, want id=%d value=%v sort=%q", *next.cursor, row.ID, tt.value, tt.sort)
+			}
+		})
+	}
+}
+
+func TestListCursorRejectedForOtherSort(t *testing.T) {
+	tx := &gorm.DB{Config: &gorm.Config{NamingStrategy: schema.NamingStrategy{}}}
+	row := listQueryTestRow{ID: 1, Name: "Luna"}
+
+	query, err := ParseListQuery(url.Values{"sort": {"name"}}, testListSpec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := cursorFor(tx, &row, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Un cursor de una página ordenada por nombre no sirve para otro orden
+	for _, sort := range []string{"-name", "created", ""} {
+		values := url.Values{"cursor": {raw}}
+		if sort != "" {
+			values.Set("sort", sort)
+		}
+		if _, err := ParseListQuery(values, testListSpec); !errors.Is(err, ErrInvalidListQuery) {
+			t.Errorf("sort %q: error = %v, want ErrInvalidListQuery", sort, err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmptyMeasurement = errors.New("measurement needs at least one of weight, temperature or heart rate")
+	ErrInvalidBucket    = errors.New("bucket must be day, week or month")
+)
+
+type MeasurementPoint struct {
+	Start       time.Time `json:"start"`
+	Count       int       `json:"count"`
+	Weight      *float64  `json:"weight"`
+	Temperature *float64  `json:"temperature"`
+	HeartRate   *float64  `json:"heart_rate"`
+}
+
+func IsEmptyMeasurement(m *model.PetMeasurement) bool {
+	return m.Weight == nil && m.Temperature == nil && m.HeartRate == nil
+}
+
+// RecordMeasurement appends a measurement to the pet's time series. When it carries the
+// most recent weight, Pet.Weight is kept in sync so listings still show the latest value.
+func RecordMeasurement(tx *gorm.DB, measurement *model.PetMeasurement) error {
+	if IsEmptyMeasurement(measurement) {
+		return ErrEmptyMeasurement
+	}
+	if measurement.MeasuredAt.IsZero() {
+		measurement.MeasuredAt = time.Now()
+	}
+
+	return tx.Transaction(func(tx *gorm.DB) error {
+		measurement.ID = 0
+		if err := tx.Create(measurement).Error; err != nil {
+			return err
+		}
+
+		if measurement.Weight == nil {
+			return nil
+		}
+
+		var newer int64
+		err := tx.Model(&model.PetMeasurement{}).
+			Where("pet_id = ? AND weight IS NOT NULL AND measured_at > ?", measurement.PetID, measurement.MeasuredAt).
+			Count(&newer).Error
+		if err != nil || newer > 0 {
+			return err
+		}
+
+		return tx.Model(&model.Pet{}).Where("id = ?", measurement.PetID).Update("weight", *measurement.Weight).Error
+	})
+}
+
+// DownsampleMeasurements averages measurements into day, week or month buckets.
+// Measurements must be sorted by MeasuredAt.
+func DownsampleMeasurements(measurements []model.PetMeasurement, bucket string) ([]MeasurementPoint, error) {
+	var truncate func(time.Time) time.Time
+	switch bucket {
+	case "day":
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		}
+	case "week":
+		truncate = func(t time.Time) time.Time {
+			start, _, _ := AppointmentPeriod(t, "week")
+			return start
+		}
+	case "month":
+		truncate = func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+		}
+	default:
+		return nil, ErrInvalidBucket
+	}
+
+	type sums struct {
+		weight, temperature, heartRate    float64
+		weights, temperatures, heartRates int
+	}
+
+	var points []MeasurementPoint
+	var current sums
+	flush := func() {
+		last := &points[len(points)-1]
+		last.Weight = average(current.weight, current.weights)
+		last.Temperature = average(current.temperature, current.temperatures)
+		last.HeartRate = average(current.heartRate, current.heartRates)
+	}
+
+	for _, measurement := range measurements {
+		start := truncate(measurement.MeasuredAt.In(time.Local))
+		if len(points) == 0 || !points[len(points)-1].Start.Equal(start) {
+			if len(points) > 0 {
+				flush()
+			}
+			points = append(points, MeasurementPoint{Start: start})
+			current = sums{}
+		}
+
+		points[len(points)-1].Count++
+		if measurement.Weight != nil {
+			current.weight += *measurement.Weight
+			current.weights++
+		}
+		if measurement.Temperature != nil {
+			current.temperature += *measurement.Temperature
+			current.temperatures++
+		}
+		if measurement.HeartRate != nil {
+			current.heartRate += float64(*measurement.HeartRate)
+			current.heartRates++
+		}
+	}
+	if len(points) > 0 {
+		flush()
+	}
+
+	return points, nil
+}
+
+func average(sum float64, count int) *float64 {
+	if count == 0 {
+		return nil
+	}
+	value := sum / float64(count)
+	return &value
+}
+
+// ParseBirthDate sets Pet.BirthDate from the YYYY-MM-DD value in BirthDateRaw.
+func ParseBirthDate(pet *model.Pet) error {
+	if pet.BirthDateRaw == "" {
+		pet.BirthDate = nil
+		return nil
+	}
+
+	parsed, err := time.ParseInLocation("2006-01-02", pet.BirthDateRaw, time.Local)
+	if err != nil {
+		return err
+	}
+	if parsed.After(time.Now()) {
+		return errors.New("birth date cannot be in the future")
+	}
+
+	pet.BirthDate = &parsed
+	return nil
+}
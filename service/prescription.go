@@ -0,0 +1,99 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCannotPrescribe       = errors.New("employee type is not allowed to prescribe")
+	ErrPrescriptionNotIssued = errors.New("only issued prescriptions can be filled")
+)
+
+// CanPrescribe reports whether the employee's type is allowed to issue prescriptions.
+func CanPrescribe(tx *gorm.DB, employeeID uint) (bool, error) {
+	employee := model.Employee{}
+	if err := tx.Preload("EmployeeType").First(&employee, employeeID).Error; err != nil {
+		return false, err
+	}
+	return employee.EmployeeType.CanPrescribe, nil
+}
+
+// FillPrescription marks the prescription as filled and, when it is linked to an
+// inventory product, dispenses the prescribed quantity against the pet.
+func FillPrescription(tx *gorm.DB, prescriptionID, employeeID uint) (model.Prescription, error) {
+	prescription := model.Prescription{}
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&prescription, prescriptionID).Error; err != nil {
+			return err
+		}
+		if prescription.Status != model.PrescriptionStatusIssued {
+			return ErrPrescriptionNotIssued
+		}
+
+		if prescription.ProductID != nil && prescription.Quantity > 0 {
+			_, err := RemoveStock(tx, StockRemoval{
+				ProductID:  *prescription.ProductID,
+				Quantity:   prescription.Quantity,
+				Kind:       model.StockMovementDispense,
+				PetID:      &prescription.PetID,
+				EmployeeID: &employeeID,
+				Reason:     fmt.Sprintf("prescription #%d", prescription.ID),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		prescription.Status = model.PrescriptionStatusFilled
+		prescription.FilledAt = &now
+		prescription.FilledByID = &employeeID
+		return tx.Model(&prescription).Select("Status", "FilledAt", "FilledByID").Updates(&prescription).Error
+	})
+
+	return prescription, err
+}
+
+// RenderPrescription formats a prescription as a printable plain-text document.
+// Pet and Employee must be preloaded.
+func RenderPrescription(prescription model.Prescription, owners []OwnerContact) string {
+	var b strings.Builder
+	line := strings.Repeat("=", 60)
+
+	fmt.Fprintln(&b, line)
+	fmt.Fprintf(&b, "PRESCRIPTION #%d\n", prescription.ID)
+	fmt.Fprintf(&b, "Date: %s\n", prescription.CreatedAt.Format("2006-01-02"))
+	fmt.Fprintln(&b, line)
+
+	pet := prescription.Pet
+	fmt.Fprintf(&b, "Patient: %s (%s, %s)\n", pet.Name, pet.Specie, pet.Race)
+	for _, owner := range owners {
+		fmt.Fprintf(&b, "Owner:   %s %s - %s\n", owner.FirstName, owner.LastName, owner.PhoneNumber)
+	}
+	fmt.Fprintln(&b)
+
+	fmt.Fprintf(&b, "Drug:      %s\n", prescription.Drug)
+	fmt.Fprintf(&b, "Dosage:    %s\n", prescription.Dosage)
+	fmt.Fprintf(&b, "Frequency: %s\n", prescription.Frequency)
+	fmt.Fprintf(&b, "Duration:  %d days\n", prescription.DurationDays)
+	if prescription.Quantity > 0 {
+		fmt.Fprintf(&b, "Quantity:  %d\n", prescription.Quantity)
+	}
+	if prescription.Instructions != "" {
+		fmt.Fprintf(&b, "\nInstructions:\n%s\n", prescription.Instructions)
+	}
+
+	employee := prescription.Employee
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, line)
+	fmt.Fprintf(&b, "Prescribed by: %s %s (%s)\n", employee.FirstName, employee.LastName, employee.EmployeeType.Name)
+	fmt.Fprintln(&b, "Signature: ______________________")
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"time"
+
+	"github.com/IsraelTeo/api-paw-go/model"
+	"gorm.io/gorm"
+)
+
+type OwnerContact struct {
+	ID          uint   `json:"id"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+type DueVaccination struct {
+	Pet         model.Pet      `json:"pet"`
+	Vaccine     model.Vaccine  `json:"vaccine"`
+	LastGivenAt time.Time      `json:"last_given_at"`
+	NextDueAt   time.Time      `json:"next_due_at"`
+	Overdue     bool           `json:"overdue"`
+	Owners      []OwnerContact `json:"owners,omitempty"`
+}
+
+// latestDoseCondition keeps only the most recent dose of each vaccine per pet.
+const latestDoseCondition = `NOT EXISTS (
+	SELECT 1 FROM vaccinations newer
+	WHERE newer.pet_id = vaccinations.pet_id
+	AND newer.vaccine_id = vaccinations.vaccine_id
+	AND newer.given_at > vaccinations.given_at
+	AND newer.deleted_at IS NULL)`
+
+func NextVaccinationDue(givenAt time.Time, vaccine model.Vaccine) time.Time {
+	return givenAt.AddDate(0, 0, int(vaccine.BoosterIntervalDays))
+}
+
+// DueVaccinations returns, for every pet, the most recent dose of each vaccine whose
+// booster is due on or before until, oldest due date first.
+func DueVaccinations(tx *gorm.DB, until time.Time) ([]DueVaccination, error) {
+	var vaccinations []model.Vaccination
+	err := tx.Preload("Pet").Preload("Vaccine").
+		Where("next_due_at <= ?", until).
+		Where(latestDoseCondition).
+		Order("next_due_at").
+		Find(&vaccinations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	petIDs := make([]uint, 0, len(vaccinations))
+	for _, vaccination := range vaccinations {
+		petIDs = append(petIDs, vaccination.PetID)
+	}
+	owners, err := PetsOwnerContacts(tx, petIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	due := make([]DueVaccination, 0, len(vaccinations))
+	for _, vaccination := range vaccinations {
+		due = append(due, DueVaccination{
+			Pet:         vaccination.Pet,
+			Vaccine:     vaccination.Vaccine,
+			LastGivenAt: vaccination.GivenAt,
+			NextDueAt:   vaccination.NextDueAt,
+			Overdue:     vaccination.NextDueAt.Before(now),
+			Owners:      owners[vaccination.PetID],
+		})
+	}
+
+	return due, nil
+}
+
+// CustomerVaccinationSchedule returns the next booster of every vaccine given to the
+// customer's pets, soonest first.
+func CustomerVaccinationSchedule(tx *gorm.DB, customerID uint) ([]DueVaccination, error) {
+	var vaccinations []model.Vaccination
+	err := tx.Preload("Pet").Preload("Vaccine").
+		Where("pet_id IN (?)", tx.Table("customer_pets").Select("pet_id").Where("customer_id = ?", customerID)).
+		Where(latestDoseCondition).
+		Order("next_due_at").
+		Find(&vaccinations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	schedule := make([]DueVaccination, 0, len(vaccinations))
+	for _, vaccination := range vaccinations {
+		schedule = append(schedule, DueVaccination{
+			Pet:         vaccination.Pet,
+			Vaccine:     vaccination.Vaccine,
+			LastGivenAt: vaccination.GivenAt,
+			NextDueAt:   vaccination.NextDueAt,
+			Overdue:     vaccination.NextDueAt.Before(now),
+		})
+	}
+
+	return schedule, nil
+}
@@ -2,8 +2,14 @@ package service
 
 import (
 	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/IsraelTeo/api-paw-go/db"
+	"github.com/IsraelTeo/api-paw-go/payload"
 	"github.com/go-playground/validator/v10"
 	"gorm.io/gorm"
 )
@@ -13,6 +19,107 @@ var validate *validator.Validate
 func InitValidator() {
 	if validate == nil {
 		validate = validator.New()
+		// Report fields by their JSON names, which is what clients send.
+		validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// FieldError describes one field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// FieldErrors translates the error returned by ValidateEntity into one entry per failed
+// field. It returns nil for errors that are not validation failures.
+func FieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	fieldErrors := make([]FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Message: ruleMessage(fe),
+		})
+	}
+	return fieldErrors
+}
+
+// WriteValidationError answers input rejected by ValidateEntity, listing each failed
+// field with the rule it broke. It lives here so handler and auth share one rendering.
+func WriteValidationError(w http.ResponseWriter, err error) {
+	log.Printf("validation error: %v", err)
+
+	fieldErrors := FieldErrors(err)
+	if fieldErrors == nil {
+		response := payload.NewResponse(payload.MessageTypeError, "Bad request", nil)
+		payload.ResponseJSON(w, http.StatusBadRequest, response)
+		return
+	}
+
+	response := payload.NewValidationResponse(fieldErrors)
+	payload.ResponseJSON(w, http.StatusBadRequest, response)
+}
+
+// fieldPath drops the struct name validator puts first, leaving e.g. "lines[0].quantity".
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+func ruleMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is not set", fe.Param())
+	case "email":
+		return "must be a valid email address"
+	case "numeric":
+		return "must contain only digits"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.Join(strings.Fields(fe.Param()), ", "))
+	case "datetime":
+		return fmt.Sprintf("must match the format %s", fe.Param())
+	case "min", "max":
+		bound := "at least"
+		if fe.Tag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("must contain %s %s items", bound, fe.Param())
+		default:
+			return fmt.Sprintf("must be %s %s", bound, fe.Param())
+		}
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "gte":
+		return fmt.Sprintf("must be greater than or equal to %s", fe.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", fe.Param())
+	case "lte":
+		return fmt.Sprintf("must be less than or equal to %s", fe.Param())
+	case "gtfield":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	default:
+		return fmt.Sprintf("failed the %s rule", fe.Tag())
 	}
 }
 